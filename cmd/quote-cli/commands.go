@@ -0,0 +1,875 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"quote-cli/internal/display"
+	"quote-cli/internal/quotes"
+)
+
+// command is a single `quote-cli <name>` subcommand.
+type command struct {
+	name    string
+	args    string // positional argument synopsis for the usage line
+	summary string
+	run     func(filePath string, args []string) error
+}
+
+// commands is filled in by init, since the subcommands themselves look the
+// table up to build their usage text.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "init", args: "[flags]", summary: "Create the quotes file from the bundled starter collection", run: runInit},
+		{name: "add", args: "[flags]", summary: "Add a new quote (prompts when no flags are given)", run: runAdd},
+		{name: "rm", args: "<id>... | [search flags]", summary: "Remove quotes by id or by search result", run: runRemove},
+		{name: "edit", args: "<id> [flags]", summary: "Edit a quote's text, author or tags (prompts when no flags are given)", run: runEdit},
+		{name: "list", args: "[flags]", summary: "List every quote with its id", run: runList},
+		{name: "search", args: "[flags] [query]", summary: "Search quotes by tags, author and text", run: runSearch},
+		{name: "show", args: "<id>", summary: "Show a single quote", run: runShow},
+		{name: "tags", args: "[flags]", summary: "List all tags with their quote counts", run: runTags},
+		{name: "authors", args: "[flags]", summary: "List all authors with their quote counts", run: runAuthors},
+		{name: "undo", args: "[flags]", summary: "Undo the last change to the quotes file", run: runUndo},
+		{name: "backups", args: "list | restore <n|name> [flags]", summary: "List the quotes file's backups or restore one", run: runBackups},
+		{name: "journal", args: "[list | compact] [flags]", summary: "List the changes in the quotes file's journal or compact it", run: runJournal},
+		{name: "import", args: "[flags] <file|dir|->", summary: "Add the quotes in a CSV, JSON, fortune, Kindle clippings or Markdown file to the quotes file", run: runImport},
+		{name: "export", args: "[flags] [file]", summary: "Write every quote as CSV, JSON or a fortune file (to stdout without a file)", run: runExport},
+	}
+}
+
+// lookupCommand returns the subcommand called name, or nil.
+func lookupCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// ====================================================== \\
+//	Flag Helpers
+// ====================================================== \\
+
+// stringList is a flag.Value that collects every use of a repeatable flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// newFlagSet builds the flag set for a subcommand with a usage message that
+// matches the top level help.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd := lookupCommand(name)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: quote-cli %s %s\n\n", cmd.name, cmd.args)
+		fmt.Fprintf(out, "%s\n\nFlags:\n", cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// addFileFlag registers the -file/-f flags shared by every command.
+func addFileFlag(fs *flag.FlagSet, filePath *string) {
+	fs.StringVar(filePath, "file", *filePath, "Path to the quotes file")
+	fs.StringVar(filePath, "f", *filePath, "Short for --file")
+}
+
+// parseArgs parses flags that may appear before or after positional arguments
+// (e.g. `edit 3 --author x`) and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, &usageError{err}
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// usageError is an error in how quote-cli was called (unknown command, bad
+// flags or arguments) rather than a problem with the quotes file.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// usagef formats a usageError.
+func usagef(format string, args ...any) error {
+	return &usageError{fmt.Errorf(format, args...)}
+}
+
+// searchFlags are the filter flags shared by search and rm.
+type searchFlags struct {
+	query    string // query language, from search's positional arguments
+	tags     stringList
+	author   string
+	contains string
+	exact    bool
+	fuzzy    bool
+	regex    bool
+	anyTerm  bool
+}
+
+// register adds the search flags to fs. verb describes what is done with the
+// matches, for the help text.
+func (sf *searchFlags) register(fs *flag.FlagSet, verb string) {
+	fs.Var(&sf.tags, "tag", verb+" quotes with this tag (repeatable, all must match)")
+	fs.Var(&sf.tags, "t", "Short for --tag")
+	fs.StringVar(&sf.author, "author", "", verb+" quotes by this author")
+	fs.StringVar(&sf.author, "a", "", "Short for --author")
+	fs.StringVar(&sf.contains, "contains", "", verb+" quotes whose text contains these words")
+	fs.StringVar(&sf.contains, "c", "", "Short for --contains")
+	fs.BoolVar(&sf.anyTerm, "any", false, "Match quotes containing any of the --contains words instead of all")
+	fs.BoolVar(&sf.exact, "exact", false, "Exact match for --tag and --author, whole words for --contains (Case-insensitive)")
+	fs.BoolVar(&sf.exact, "e", false, "Short for --exact")
+	fs.BoolVar(&sf.fuzzy, "fuzzy", false, "Typo tolerant match for --tag and --author (e.g. Shakespear, Rosevelt)")
+	fs.BoolVar(&sf.regex, "regex", false, "Treat --tag, --author and --contains as Go regular expressions (case-sensitive)")
+	fs.BoolVar(&sf.regex, "r", false, "Short for --regex")
+	fs.BoolFunc("keep-accents", "Accents must match too (by default Camus finds Camús)", func(value string) error {
+		keep, err := strconv.ParseBool(value)
+		quotes.StripAccents = !keep
+		return err
+	})
+}
+
+// validate rejects flag combinations that contradict each other.
+func (sf *searchFlags) validate() error {
+	if sf.exact && sf.fuzzy {
+		return usagef("--exact and --fuzzy cannot be used together")
+	}
+	if sf.regex && (sf.exact || sf.fuzzy || sf.anyTerm) {
+		return usagef("--regex cannot be combined with --exact, --fuzzy or --any")
+	}
+	return nil
+}
+
+// empty reports whether no filter flag was given.
+func (sf *searchFlags) empty() bool {
+	return sf.query == "" && len(sf.tags) == 0 && sf.author == "" && sf.contains == ""
+}
+
+// filter combines the query and every given flag into one filter; a quote
+// has to match all of them. It fails for query syntax errors and invalid
+// --regex patterns.
+func (sf *searchFlags) filter() (quotes.Filter, error) {
+	if sf.regex {
+		return sf.regexFilter()
+	}
+
+	var filter quotes.AndFilter
+	for _, tag := range sf.tags {
+		filter = append(filter, quotes.TagFilter{Tag: tag, Exact: sf.exact, Fuzzy: sf.fuzzy})
+	}
+	if sf.author != "" {
+		filter = append(filter, quotes.AuthorFilter{Author: sf.author, Exact: sf.exact, Fuzzy: sf.fuzzy})
+	}
+	if sf.contains != "" {
+		mode := quotes.AllTerms
+		if sf.anyTerm {
+			mode = quotes.AnyTerm
+		}
+		filter = append(filter, quotes.TextFilter{Text: sf.contains, Exact: sf.exact, Mode: mode})
+	}
+	if sf.query != "" {
+		query, err := quotes.ParseQuery(sf.query, sf.exact, sf.fuzzy)
+		if err != nil {
+			var queryErr *quotes.QueryError
+			if errors.As(err, &queryErr) {
+				// show where in the query the problem is
+				return nil, &usageError{fmt.Errorf("%w\n  %s", err, strings.ReplaceAll(queryErr.Pointer(), "\n", "\n  "))}
+			}
+			return nil, &usageError{err}
+		}
+		filter = append(filter, query)
+	}
+	return filter, nil
+}
+
+// regexFilter is filter for --regex, where every flag value is a pattern.
+func (sf *searchFlags) regexFilter() (quotes.Filter, error) {
+	var filter quotes.AndFilter
+	add := func(field quotes.Field, pattern string) error {
+		regexFilter, err := quotes.NewRegexFilter(field, pattern)
+		if err != nil {
+			return &usageError{err}
+		}
+		filter = append(filter, regexFilter)
+		return nil
+	}
+
+	for _, tag := range sf.tags {
+		if err := add(quotes.FieldTag, tag); err != nil {
+			return nil, err
+		}
+	}
+	if sf.author != "" {
+		if err := add(quotes.FieldAuthor, sf.author); err != nil {
+			return nil, err
+		}
+	}
+	if sf.contains != "" {
+		if err := add(quotes.FieldText, sf.contains); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// printSuggestions writes "did you mean" hints to stderr for each searched
+// author or tag that matches nothing on its own, for when a search finds
+// nothing.
+func (sf *searchFlags) printSuggestions(idx *quotes.Index) {
+	if sf.author != "" && len(idx.Search(quotes.AuthorFilter{Author: sf.author, Exact: sf.exact, Fuzzy: sf.fuzzy})) == 0 {
+		if names := quotes.SuggestAuthors(idx.Quotes(), sf.author, 3); len(names) > 0 {
+			fmt.Fprintf(os.Stderr, "No quotes by %q, did you mean %s?\n", sf.author, strings.Join(names, ", "))
+		}
+	}
+	for _, tag := range sf.tags {
+		if len(idx.Search(quotes.TagFilter{Tag: tag, Exact: sf.exact, Fuzzy: sf.fuzzy})) > 0 {
+			continue
+		}
+		if names := quotes.SuggestTags(idx.Quotes(), tag, 3); len(names) > 0 {
+			fmt.Fprintf(os.Stderr, "No tag %q, did you mean %s?\n", tag, strings.Join(names, ", "))
+		}
+	}
+}
+
+// addIDsFlag registers the -ids flag that makes the display print quote IDs.
+func addIDsFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&display.ShowIDs, "ids", false, "Print each quote's id")
+}
+
+// addOutputFlag registers the --output/-o flag choosing the output format.
+func addOutputFlag(fs *flag.FlagSet, output *string) {
+	fs.StringVar(output, "output", display.TextOutput, "Output format: "+strings.Join(display.OutputNames(), ", "))
+	fs.StringVar(output, "o", display.TextOutput, "Short for --output")
+}
+
+// checkOutput rejects an unknown --output before any work is done.
+func checkOutput(output string) error {
+	if _, ok := display.LookupOutput(output); !ok && output != display.TextOutput {
+		return usagef("unknown output %q, expected one of %s", output, strings.Join(display.OutputNames(), ", "))
+	}
+	return nil
+}
+
+// addColorFlag registers the --color flag turning colors on or off.
+func addColorFlag(fs *flag.FlagSet) {
+	fs.Func("color", "Color output: always, never or auto (on a terminal without $NO_COLOR)", configureColor)
+}
+
+// addFormatFlag registers the --format flag taking a template, or the name of
+// one, to print each quote with.
+func addFormatFlag(fs *flag.FlagSet, format *string) {
+	fs.StringVar(format, "format", "", "Go template to print each quote with, e.g. '{{.Text}} — {{.Author}}', or a template name")
+}
+
+// newRenderer returns the stdout renderer for layout, printing through the
+// --format template instead when one is given.
+func newRenderer(layout display.Layout, format, output string) (*display.Renderer, error) {
+	renderer := display.NewRenderer(os.Stdout, layout)
+	renderer.ShowIDs = display.ShowIDs
+	renderer.Theme = display.ColorTheme
+	if format == "" {
+		return renderer, nil
+	}
+	if output != display.TextOutput {
+		return nil, usagef("--format cannot be combined with --output")
+	}
+
+	tmpl, err := display.LoadTemplate(format)
+	if err != nil {
+		return nil, &usageError{err}
+	}
+	renderer.Template = tmpl
+	return renderer, nil
+}
+
+// parseQuoteIDs converts every positional argument of rm into a quote ID.
+func parseQuoteIDs(positional []string) ([]int, error) {
+	if len(positional) == 0 {
+		return nil, usagef("expected at least one quote id")
+	}
+
+	ids := make([]int, 0, len(positional))
+	for _, arg := range positional {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, usagef("invalid quote id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// parseQuoteID reads the single <id> argument of edit/show and returns the
+// slice index of the quote with that ID in quoteList, loaded from filePath.
+func parseQuoteID(positional []string, quoteList []quotes.Quote, filePath string) (int, error) {
+	if len(positional) != 1 {
+		return 0, usagef("expected exactly one quote id, got %d arguments", len(positional))
+	}
+
+	id, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return 0, usagef("invalid quote id %q", positional[0])
+	}
+
+	index := quotes.IndexOfID(quoteList, id)
+	if index < 0 {
+		return 0, &quotes.NotFoundError{ID: id, Path: filePath}
+	}
+
+	return index, nil
+}
+
+// ====================================================== \\
+//	Subcommands
+// ====================================================== \\
+
+func runInit(filePath string, args []string) error {
+	var force bool
+
+	fs := newFlagSet("init")
+	addFileFlag(fs, &filePath)
+	fs.BoolVar(&force, "force", false, "Replace an existing quotes file (its quotes are lost)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	err := quotes.WriteStarterFile(force, filePath)
+	if errors.Is(err, iofs.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to replace it", filePath)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d starter quotes to %s\n", len(quotes.StarterQuotes()), filePath)
+	return nil
+}
+
+func runAdd(filePath string, args []string) error {
+	var text, author string
+	tags := stringList{}
+
+	fs := newFlagSet("add")
+	addFileFlag(fs, &filePath)
+	fs.StringVar(&text, "text", "", "Quote text")
+	fs.StringVar(&author, "author", "", "Quote author")
+	fs.StringVar(&author, "a", "", "Short for --author")
+	fs.Var(&tags, "tag", "Quote tag (repeatable)")
+	fs.Var(&tags, "t", "Short for --tag")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+
+	// no text given, fall back to the interactive prompt
+	if text == "" {
+		return display.DisplayQuoteAdditionPrompt(filePath)
+	}
+
+	return quotes.AddNewQuote(text, author, tags, filePath)
+}
+
+func runRemove(filePath string, args []string) error {
+	var search searchFlags
+	var yes bool
+
+	fs := newFlagSet("rm")
+	addFileFlag(fs, &filePath)
+	search.register(fs, "Remove")
+	fs.BoolVar(&yes, "yes", false, "Do not ask before removing search results")
+	fs.BoolVar(&yes, "y", false, "Short for --yes")
+	addColorFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+
+	// remove by id
+	if search.empty() {
+		ids, err := parseQuoteIDs(positional)
+		if err != nil {
+			return err
+		}
+		_, err = quotes.RemoveQuotes(ids, filePath)
+		return err
+	}
+
+	// remove by search result
+	if len(positional) > 0 {
+		return usagef("give either quote ids or search flags, not both")
+	}
+	if err := search.validate(); err != nil {
+		return err
+	}
+	idx, err := quotes.LoadIndexFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	filter, err := search.filter()
+	if err != nil {
+		return err
+	}
+	foundQuotes := idx.Search(filter)
+	if len(foundQuotes) == 0 {
+		fmt.Println("No quotes matched, nothing removed")
+		return nil
+	}
+
+	if !yes {
+		display.ShowIDs = true
+		display.DisplayQuoteListWraped(foundQuotes)
+		if !display.DisplayConfirmPrompt(fmt.Sprintf("Remove these %d quotes?", len(foundQuotes))) {
+			fmt.Println("Nothing removed")
+			return nil
+		}
+	}
+
+	ids := make([]int, 0, len(foundQuotes))
+	for _, quote := range foundQuotes {
+		ids = append(ids, quote.ID)
+	}
+	removed, err := quotes.RemoveQuotes(ids, filePath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d quotes\n", len(removed))
+
+	return nil
+}
+
+func runEdit(filePath string, args []string) error {
+	var text, author string
+	var tags stringList
+	var clearTags bool
+
+	fs := newFlagSet("edit")
+	addFileFlag(fs, &filePath)
+	fs.StringVar(&text, "text", "", "New quote text")
+	fs.StringVar(&author, "author", "", "New quote author")
+	fs.StringVar(&author, "a", "", "Short for --author")
+	fs.Var(&tags, "tag", "Replace the tags with these (repeatable)")
+	fs.Var(&tags, "t", "Short for --tag")
+	fs.BoolVar(&clearTags, "clear-tags", false, "Remove every tag from the quote")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
+	if err != nil {
+		return err
+	}
+	index, err := parseQuoteID(positional, quoteList, filePath)
+	if err != nil {
+		return err
+	}
+	quote := quoteList[index]
+
+	// no flags given, edit interactively
+	if text == "" && author == "" && len(tags) == 0 && !clearTags {
+		return display.DisplayQuoteEditPrompt(quote, filePath)
+	}
+
+	// applied to the quote as it is when the file is locked, so edits to
+	// other fields in the meantime are kept
+	return quotes.EditQuote(quote.ID, func(quote *quotes.Quote) {
+		if text != "" {
+			quote.Text = text
+		}
+		if author != "" {
+			quote.Author = author
+		}
+		if clearTags {
+			quote.Tags = []string{}
+		}
+		if len(tags) > 0 {
+			quote.Tags = tags
+		}
+	}, filePath)
+}
+
+func runList(filePath string, args []string) error {
+	var output, format string
+
+	fs := newFlagSet("list")
+	addFileFlag(fs, &filePath)
+	addOutputFlag(fs, &output)
+	addFormatFlag(fs, &format)
+	addColorFlag(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if err := checkOutput(output); err != nil {
+		return err
+	}
+	display.ShowIDs = true
+	renderer, err := newRenderer(display.LayoutWrapped, format, output)
+	if err != nil {
+		return err
+	}
+
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if output != display.TextOutput {
+		return display.WriteOutput(os.Stdout, output, quoteList, false)
+	}
+	return renderer.RenderList(quoteList)
+}
+
+func runSearch(filePath string, args []string) error {
+	var search searchFlags
+	var sortFlag, output, format string
+	var jsonFlag bool
+
+	fs := newFlagSet("search")
+	addFileFlag(fs, &filePath)
+	addIDsFlag(fs)
+	search.register(fs, "Find")
+	fs.StringVar(&sortFlag, "sort", "relevance", "Result order: relevance, author or added")
+	fs.BoolVar(&jsonFlag, "json", false, "Short for --output json")
+	addOutputFlag(fs, &output)
+	addFormatFlag(fs, &format)
+	addColorFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if jsonFlag {
+		if output != display.TextOutput && output != "json" {
+			return usagef("--json cannot be combined with --output %s", output)
+		}
+		output = "json"
+	}
+	if err := checkOutput(output); err != nil {
+		return err
+	}
+	renderer, err := newRenderer(display.LayoutWrapped, format, output)
+	if err != nil {
+		return err
+	}
+
+	// positional arguments are a query, or with --regex a text pattern
+	if len(positional) > 0 {
+		words := strings.Join(positional, " ")
+		if !search.regex {
+			search.query = words
+		} else if search.contains != "" {
+			search.contains += " " + words
+		} else {
+			search.contains = words
+		}
+	}
+
+	if search.empty() {
+		return usagef("search needs a query or at least one of --tag, --author or --contains")
+	}
+	if err := search.validate(); err != nil {
+		return err
+	}
+	order, err := quotes.ParseSortOrder(sortFlag)
+	if err != nil {
+		return &usageError{err}
+	}
+
+	idx, err := quotes.LoadIndexFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	filter, err := search.filter()
+	if err != nil {
+		return err
+	}
+	// rank by every word searched for, so quotes matching them in the author
+	// or tags come first
+	rankQuery := strings.Join(quotes.QueryWords(filter), " ")
+	results := idx.Rank(filter, rankQuery, search.exact)
+	quotes.SortResults(results, order)
+	if len(results) == 0 && output == display.TextOutput && format == "" && !search.regex {
+		search.printSuggestions(idx)
+	}
+
+	if output != display.TextOutput {
+		return display.WriteResults(os.Stdout, output, results)
+	}
+	return renderer.RenderList(quotes.ResultQuotes(results))
+}
+
+func runShow(filePath string, args []string) error {
+	var output, format string
+
+	fs := newFlagSet("show")
+	addFileFlag(fs, &filePath)
+	addIDsFlag(fs)
+	addOutputFlag(fs, &output)
+	addFormatFlag(fs, &format)
+	addColorFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if err := checkOutput(output); err != nil {
+		return err
+	}
+	renderer, err := newRenderer(display.LayoutBordered, format, output)
+	if err != nil {
+		return err
+	}
+
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
+	if err != nil {
+		return err
+	}
+	index, err := parseQuoteID(positional, quoteList, filePath)
+	if err != nil {
+		return err
+	}
+
+	if output != display.TextOutput {
+		return display.WriteOutput(os.Stdout, output, quoteList[index:index+1], true)
+	}
+	return renderer.Render(quoteList[index])
+}
+
+func runTags(filePath string, args []string) error {
+	fs := newFlagSet("tags")
+	addFileFlag(fs, &filePath)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	printCounts(quotes.CountTags(quoteList))
+	return nil
+}
+
+func runAuthors(filePath string, args []string) error {
+	fs := newFlagSet("authors")
+	addFileFlag(fs, &filePath)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	printCounts(quotes.CountAuthors(quoteList))
+	return nil
+}
+
+func runUndo(filePath string, args []string) error {
+	fs := newFlagSet("undo")
+	addFileFlag(fs, &filePath)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("unexpected argument %q", positional[0])
+	}
+
+	changed, err := quotes.Undo(filePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undid the change to %s made at %s\n", filePath, formatTime(changed))
+	return nil
+}
+
+func runBackups(filePath string, args []string) error {
+	fs := newFlagSet("backups")
+	addFileFlag(fs, &filePath)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usagef("expected list or restore")
+	}
+
+	backups, err := quotes.ListBackups(filePath)
+	if err != nil {
+		return err
+	}
+
+	switch action := positional[0]; {
+	case action == "list" && len(positional) == 1:
+		if len(backups) == 0 {
+			fmt.Printf("No backups of %s yet\n", filePath)
+			return nil
+		}
+		for i, backup := range backups {
+			count := "unreadable"
+			if quoteList, err := quotes.LoadQuotesFromFile(backup.Path); err == nil {
+				count = fmt.Sprintf("%d quotes", len(quoteList))
+			} else if errors.Is(err, quotes.ErrEmptyCollection) {
+				count = "0 quotes"
+			}
+			fmt.Printf("%3d  %s  %-10s  %s\n", i+1, formatTime(backup.Time), count, backup.Name())
+		}
+		return nil
+
+	case action == "restore" && len(positional) == 2:
+		// a number from `backups list`, or a backup file name
+		name := positional[1]
+		if n, err := strconv.Atoi(name); err == nil {
+			if n < 1 || n > len(backups) {
+				return usagef("no backup number %d, see 'quote-cli backups list'", n)
+			}
+			name = backups[n-1].Name()
+		}
+
+		backup, err := quotes.RestoreBackup(name, filePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s to how it was at %s ('quote-cli undo' to go back)\n", filePath, formatTime(backup.Time))
+		return nil
+	}
+
+	return usagef("expected list or restore <n|name>")
+}
+
+func runJournal(filePath string, args []string) error {
+	fs := newFlagSet("journal")
+	addFileFlag(fs, &filePath)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+
+	action := "list"
+	if len(positional) > 0 {
+		action = positional[0]
+	}
+	if len(positional) > 1 {
+		return usagef("unexpected argument %q", positional[1])
+	}
+
+	switch action {
+	case "list":
+		entries, err := quotes.ReadJournal(filePath)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No changes in the journal of %s\n", filePath)
+			return nil
+		}
+		for _, entry := range entries {
+			quote := entry.Quote
+			if quote == nil {
+				quote = entry.Old
+			}
+			text := ""
+			if quote != nil {
+				text = quote.Text
+			}
+			fmt.Printf("%s  %-10s  %-6s  %4d  %s\n", formatTime(entry.Time), entry.User, entry.Op, entry.ID, text)
+		}
+		return nil
+
+	case "compact":
+		if err := quotes.CompactJournal(filePath); err != nil {
+			return err
+		}
+		fmt.Printf("Compacted the journal into %s\n", filePath)
+		return nil
+	}
+
+	return usagef("expected list or compact")
+}
+
+// formatTime formats t in local time, for backup and journal listings.
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// printCounts prints name/count pairs sorted by name, one per line.
+func printCounts(counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("%5d  %s\n", counts[name], name)
+	}
+}
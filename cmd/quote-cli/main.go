@@ -1,12 +1,14 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"quote-cli/internal/display"
 	"quote-cli/internal/quotes"
@@ -20,6 +22,8 @@ const testFilePath = "_assets/small.json"
 // path for real build
 const appConfigRelativePath = "quote-cli"
 const configFileName = "default.json"
+const templateDirName = "templates"
+const themeDirName = "themes"
 
 // getDefaultConfigPath returns the full path to the default configuration file
 // in an OS-idiomatic location.
@@ -38,95 +42,312 @@ func getDefaultConfigPath() (string, error) {
 	return fullPath, nil
 }
 
+// Exit codes, so scripts can tell failures apart.
+const (
+	exitError    = 1 // any failure not listed below
+	exitUsage    = 2 // unknown command, bad flags, arguments or query
+	exitNotFound = 3 // no quote with the given id
+	exitEmpty    = 4 // the quotes file holds no quotes
+	exitParse    = 5 // the quotes file is not valid JSON
+	exitWrite    = 6 // the quotes file could not be written
+	exitLocked   = 7 // another process holds the quotes file's lock
+)
+
 func main() {
+	err := run(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "quote-cli: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	var usageErr *usageError
+	var queryErr *quotes.QueryError
+	var parseErr *quotes.ParseError
+	var writeErr *quotes.WriteError
+
+	switch {
+	case errors.As(err, &usageErr), errors.As(err, &queryErr):
+		return exitUsage
+	case errors.Is(err, quotes.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, quotes.ErrEmptyCollection):
+		return exitEmpty
+	case errors.As(err, &parseErr):
+		return exitParse
+	case errors.As(err, &writeErr):
+		return exitWrite
+	case errors.Is(err, quotes.ErrLocked):
+		return exitLocked
+	}
+	return exitError
+}
+
+// run dispatches args to the matching subcommand. With no subcommand
+// (bare `quote-cli` or only root flags) a random quote is displayed.
+func run(args []string) error {
 	//var filePath = testFilePath
 	filePath, err := getDefaultConfigPath()
 	if err != nil {
-		// TODO: figure out what to do with this err...
-		return
+		return err
+	}
+	defaultFilePath = filePath
+	if err := configureBackups(); err != nil {
+		return err
+	}
+	if err := configureJournal(); err != nil {
+		return err
+	}
+	display.TemplateDir = filepath.Join(filepath.Dir(filePath), templateDirName)
+	display.ThemeDir = filepath.Join(filepath.Dir(filePath), themeDirName)
+	if err := configureTheme(); err != nil {
+		return err
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runRandom(filePath, args)
+	}
+
+	name, rest := args[0], args[1:]
+	if name == "help" {
+		return runHelp(filePath, rest)
+	}
+
+	cmd := lookupCommand(name)
+	if cmd == nil {
+		printUsage()
+		return usagef("unknown command %q", name)
 	}
 
-	// Define Command-Line Flags
-	var quotesFilePathFlag string
-	var quotesTagSearchFlag string
-	var quotesAuthorSearchFlag string
+	return cmd.run(filePath, rest)
+}
+
+// backupCountEnv overrides how many backups of the quotes file are kept.
+const backupCountEnv = "QUOTE_CLI_BACKUPS"
+
+// configureBackups applies $QUOTE_CLI_BACKUPS to quotes.BackupCount.
+func configureBackups() error {
+	value, ok := os.LookupEnv(backupCountEnv)
+	if !ok || value == "" {
+		return nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return usagef("%s must be a number of backups to keep (0 for none), got %q", backupCountEnv, value)
+	}
+	quotes.BackupCount = count
+	return nil
+}
+
+// journalEnv turns the change journal on ("1") or off ("0").
+const journalEnv = "QUOTE_CLI_JOURNAL"
+
+// configureJournal applies $QUOTE_CLI_JOURNAL to quotes.UseJournal.
+func configureJournal() error {
+	value, ok := os.LookupEnv(journalEnv)
+	if !ok || value == "" {
+		return nil
+	}
+
+	useJournal, err := strconv.ParseBool(value)
+	if err != nil {
+		return usagef("%s must be 1 or 0, got %q", journalEnv, value)
+	}
+	quotes.UseJournal = useJournal
+	return nil
+}
+
+// themeEnv picks the color theme, by name.
+const themeEnv = "QUOTE_CLI_THEME"
+
+// theme is the color theme from $QUOTE_CLI_THEME, used whenever colors are on.
+var theme display.Theme
+
+// configureTheme loads the theme named by $QUOTE_CLI_THEME and colors output
+// if stdout is a terminal; --color changes that later.
+func configureTheme() error {
+	name := os.Getenv(themeEnv)
+	if name == "" {
+		name = display.DefaultTheme
+	}
+
+	var err error
+	if theme, err = display.LoadTheme(name); err != nil {
+		return usagef("%s: %v", themeEnv, err)
+	}
+	return configureColor(display.ColorAuto)
+}
+
+// configureColor sets display.ColorTheme for a --color mode.
+func configureColor(mode string) error {
+	isColor, err := display.UseColor(mode, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	display.ColorTheme = display.Theme{}
+	if isColor {
+		display.ColorTheme = theme
+	}
+	return nil
+}
+
+// defaultFilePath is the quotes file used when -f is not given.
+var defaultFilePath string
+
+// seedQuotesFile creates the default quotes file from the starter collection
+// on first run. Commands call it once their flags are parsed, so it only
+// happens when they go on to use the default file; a file given with -f is
+// never created, and init creates the file itself.
+func seedQuotesFile(filePath string) error {
+	if filePath != defaultFilePath {
+		return nil
+	}
+
+	created, err := quotes.EnsureQuotesFile(filePath)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(os.Stderr, "Created %s with %d starter quotes\n", filePath, len(quotes.StarterQuotes()))
+	}
+	return nil
+}
+
+// runRandom is the default path: print one random quote in a border.
+func runRandom(filePath string, args []string) error {
 	var versionFlag bool
-	var quoteAdditionFlag bool
-	var exactMatchFlag bool
-
-	// src file
-	flag.StringVar(&quotesFilePathFlag, "file", filePath, "Path to the quotes file")
-	flag.StringVar(&quotesFilePathFlag, "f", filePath, "Path to the quotes file")
-	//tag search
-	flag.StringVar(&quotesTagSearchFlag, "tag", "", "Tag to search quotes for (sub-string matching, case-insensitive)")
-	flag.StringVar(&quotesTagSearchFlag, "t", "", "Tag to search quotes for")
-	// author search
-	flag.StringVar(&quotesAuthorSearchFlag, "author", "", "Author to search quotes for (sub-string matching, case-insensitive)")
-	flag.StringVar(&quotesAuthorSearchFlag, "a", "", "Short for --author")
-	// version
-	flag.BoolVar(&versionFlag, "version", false, "Print application version")
-	flag.BoolVar(&versionFlag, "v", false, "Print application version")
-	// add new quote
-	flag.BoolVar(&quoteAdditionFlag, "new", false, "Create new quote")
-	flag.BoolVar(&quoteAdditionFlag, "n", false, "Create new quote")
-	// Exact match toggle
-	flag.BoolVar(&exactMatchFlag, "exact", false, "Enable exact match for author and tag searches (Case-insensitive)")
-	flag.BoolVar(&exactMatchFlag, "e", false, "Short for --exact")
-	flag.Parse()
+	var output, format string
+
+	fs := flag.NewFlagSet("quote-cli", flag.ContinueOnError)
+	fs.Usage = printUsage
+	addFileFlag(fs, &filePath)
+	addIDsFlag(fs)
+	addOutputFlag(fs, &output)
+	addFormatFlag(fs, &format)
+	addColorFlag(fs)
+	fs.BoolVar(&versionFlag, "version", false, "Print application version")
+	fs.BoolVar(&versionFlag, "v", false, "Print application version")
+
+	// the flags from before subcommands, kept working for old scripts
+	var tag, author string
+	var isNew, isExact bool
+	fs.StringVar(&tag, "tag", "", "Deprecated, use search -t")
+	fs.StringVar(&tag, "t", "", "Deprecated, use search -t")
+	fs.StringVar(&author, "author", "", "Deprecated, use search -a")
+	fs.StringVar(&author, "a", "", "Deprecated, use search -a")
+	fs.BoolVar(&isExact, "exact", false, "Deprecated, use search -e")
+	fs.BoolVar(&isExact, "e", false, "Deprecated, use search -e")
+	fs.BoolVar(&isNew, "new", false, "Deprecated, use add")
+	fs.BoolVar(&isNew, "n", false, "Deprecated, use add")
+
+	if err := fs.Parse(args); err != nil {
+		return &usageError{err}
+	}
+	if fs.NArg() > 0 {
+		printUsage()
+		return usagef("unexpected argument %q", fs.Arg(0))
+	}
+	if err := checkOutput(output); err != nil {
+		return err
+	}
+
+	if isNew {
+		deprecated("--new", "quote-cli add")
+		return runAdd(filePath, nil)
+	}
+	if tag != "" || author != "" {
+		searchArgs := []string{"-o", output}
+		if format != "" {
+			searchArgs = append(searchArgs, "--format", format)
+		}
+		if tag != "" {
+			deprecated("--tag", "quote-cli search -t <tag>")
+			searchArgs = append(searchArgs, "-t", tag)
+		}
+		if author != "" {
+			deprecated("--author", "quote-cli search -a <author>")
+			searchArgs = append(searchArgs, "-a", author)
+		}
+		if isExact {
+			searchArgs = append(searchArgs, "-e")
+		}
+		return runSearch(filePath, searchArgs)
+	}
+	renderer, err := newRenderer(display.LayoutBordered, format, output)
+	if err != nil {
+		return err
+	}
 
 	// Display program version
 	if versionFlag {
 		fmt.Printf("Quote CLI Version: %s\n", appVersion)
-		return
+		return nil
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
 	}
 
 	// Load Quotes
-	quoteList, err := quotes.LoadQuotesFromFile(quotesFilePathFlag)
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
 	if err != nil {
-		log.Fatalf("Error loading quotes: %v", err)
+		return fmt.Errorf("loading quotes: %w", err)
 	}
 
-	// quote addition
-	if quoteAdditionFlag {
-		display.DisplayQuoteAdditionPrompt(filePath)
-		return
+	// Display Random Quote
+	randomInt := rand.Intn(len(quoteList))
+	if output != display.TextOutput {
+		return display.WriteOutput(os.Stdout, output, quoteList[randomInt:randomInt+1], true)
 	}
+	//display.DisplayQuoteWraped(quoteList[randomInt])
+	return renderer.Render(quoteList[randomInt])
+}
 
-	// quote searching
-	if quotesTagSearchFlag != "" {
-		var foundQuotes []quotes.Quote
-
-		// Handle tag search
-		if exactMatchFlag {
-			foundQuotes = quotes.SearchByQuoteTag(quoteList, quotesTagSearchFlag, true)
-		} else {
-			foundQuotes = quotes.SearchByQuoteTag(quoteList, quotesTagSearchFlag, false)
-		}
-
-		if err != nil {
-			log.Fatalf("Error with search: %v", err)
-		}
-		display.DisplayQuoteListWraped(foundQuotes)
+// deprecated warns on stderr that the root flag old is going away.
+func deprecated(old, replacement string) {
+	fmt.Fprintf(os.Stderr, "quote-cli: %s is deprecated and will be removed, use `%s` instead\n", old, replacement)
+}
 
-	} else if quotesAuthorSearchFlag != "" {
-		// Handle Author search
-		var foundQuotes []quotes.Quote
+// runHelp prints the top level usage, or the usage of a single subcommand.
+func runHelp(filePath string, args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return nil
+	}
 
-		if exactMatchFlag {
-			foundQuotes = quotes.SearchByQuoteAuthor(quoteList, quotesAuthorSearchFlag, true)
-		} else {
-			foundQuotes = quotes.SearchByQuoteAuthor(quoteList, quotesAuthorSearchFlag, false)
-		}
+	cmd := lookupCommand(args[0])
+	if cmd == nil {
+		printUsage()
+		return usagef("unknown command %q", args[0])
+	}
 
-		if err != nil {
-			log.Fatalf("Error with search: %v", err)
-		}
-		display.DisplayQuoteListWraped(foundQuotes)
+	// every subcommand answers -h with its own usage
+	return cmd.run(filePath, []string{"-h"})
+}
 
-	} else {
-		// Display Random Quote
-		randomInt := rand.Intn(len(quoteList))
-		display.DisplayQuoteWrapedBoarder(quoteList[randomInt])
-		//display.DisplayQuoteWraped(quoteList[randomInt])
+// printUsage writes the top level help text to stderr.
+func printUsage() {
+	out := os.Stderr
+	fmt.Fprintf(out, "Usage:\n")
+	fmt.Fprintf(out, "  quote-cli [-f file]            print a random quote\n")
+	fmt.Fprintf(out, "  quote-cli <command> [flags]\n\n")
+	fmt.Fprintf(out, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(out, "  %-10s %s\n", "help", "Show help for a command")
+	fmt.Fprintf(out, "\nFlags:\n")
+	fmt.Fprintf(out, "  -f, --file     Path to the quotes file\n")
+	fmt.Fprintf(out, "  --ids          Print the quote's id\n")
+	fmt.Fprintf(out, "  -o, --output   Output format: %s\n", strings.Join(display.OutputNames(), ", "))
+	fmt.Fprintf(out, "  --format       Template for each quote, or one of: %s\n", strings.Join(display.TemplateNames(), ", "))
+	fmt.Fprintf(out, "  --color        Color output: always, never or auto\n")
+	fmt.Fprintf(out, "  -v, --version  Print application version\n")
+	fmt.Fprintf(out, "\nRun 'quote-cli help <command>' for command flags.\n")
 }
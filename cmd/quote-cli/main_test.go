@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"quote-cli/internal/quotes"
+)
+
+//				Test - exitCode
+// ====================================================== \\
+
+// TestExitCode tests that each kind of error, wrapped or not, exits with its
+// own code.
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "Usage error", err: usagef("unknown command %q", "x"), expectedCode: exitUsage},
+		{name: "Query error", err: &quotes.QueryError{Query: "tag:", Column: 5, Msg: "missing value after tag:"}, expectedCode: exitUsage},
+		{name: "Not found", err: quotes.ErrNotFound, expectedCode: exitNotFound},
+		{name: "Empty collection", err: quotes.ErrEmptyCollection, expectedCode: exitEmpty},
+		{name: "Parse error", err: &quotes.ParseError{Path: "default.json", Line: 1, Column: 2, Err: errors.New("bad json")}, expectedCode: exitParse},
+		{name: "Write error", err: &quotes.WriteError{Path: "default.json", Err: errors.New("disk full")}, expectedCode: exitWrite},
+		{name: "Locked", err: quotes.ErrLocked, expectedCode: exitLocked},
+		{name: "Other error", err: errors.New("something else"), expectedCode: exitError},
+		{name: "Wrapped usage error", err: fmt.Errorf("search: %w", usagef("no query")), expectedCode: exitUsage},
+		{name: "Wrapped query error", err: fmt.Errorf("search: %w", &quotes.QueryError{Query: "(", Column: 2, Msg: "unclosed"}), expectedCode: exitUsage},
+		{name: "Wrapped not found", err: fmt.Errorf("%w: id 7", quotes.ErrNotFound), expectedCode: exitNotFound},
+		{name: "Wrapped empty collection", err: fmt.Errorf("%w in %q", quotes.ErrEmptyCollection, "default.json"), expectedCode: exitEmpty},
+		{name: "Wrapped parse error", err: fmt.Errorf("import: %w", &quotes.ParseError{Path: "in.json", Err: errors.New("bad json")}), expectedCode: exitParse},
+		{name: "Wrapped write error", err: fmt.Errorf("add: %w", &quotes.WriteError{Path: "default.json", Err: errors.New("disk full")}), expectedCode: exitWrite},
+		{name: "Wrapped locked", err: fmt.Errorf("%w by another process", quotes.ErrLocked), expectedCode: exitLocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := exitCode(tt.err); code != tt.expectedCode {
+				t.Errorf("exitCode(%v) \ngot  = %v, \nwant = %v", tt.err, code, tt.expectedCode)
+			}
+		})
+	}
+}
+
+//				Test - run
+// ====================================================== \\
+
+// TestRun_UsageErrors tests that an unknown command is a usage error and that
+// -h asks for help rather than failing.
+func TestRun_UsageErrors(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, name := range []string{backupCountEnv, journalEnv, themeEnv} {
+		t.Setenv(name, "")
+	}
+
+	err := run([]string{"nonsense"})
+	if errors.Is(err, flag.ErrHelp) || exitCode(err) != exitUsage {
+		t.Errorf("run(nonsense) error = %v, exit code %d; want a usage error", err, exitCode(err))
+	}
+
+	if err := run([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("run(-h) error = %v; want flag.ErrHelp", err)
+	}
+}
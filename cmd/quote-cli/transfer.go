@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"quote-cli/internal/display"
+	"quote-cli/internal/quotes"
+)
+
+// ====================================================== \\
+//	Import / Export
+// ====================================================== \\
+
+// importFormats and exportFormats are the file formats import and export
+// understand.
+var (
+	importFormats = []string{"json", "csv", "fortune", "kindle", "markdown"}
+	exportFormats = []string{"json", "csv", "fortune"}
+)
+
+func runImport(filePath string, args []string) error {
+	fs := newFlagSet("import")
+	addFileFlag(fs, &filePath)
+	var format, delimiter string
+	var dryRun bool
+	fs.StringVar(&format, "format", "", "Format of the file: "+strings.Join(importFormats, ", ")+" (default from the file name, else json)")
+	fs.StringVar(&delimiter, "delimiter", ",", "Field delimiter for csv, e.g. ';' or '\\t'")
+	fs.BoolVar(&dryRun, "dry-run", false, "Only print the quotes that would be added")
+	fs.BoolVar(&dryRun, "n", false, "Short for --dry-run")
+	addColorFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("expected one file to import (a directory of notes for markdown), or - for stdin")
+	}
+
+	source := positional[0]
+	format, err = transferFormat(format, source, importFormats)
+	if err != nil {
+		return err
+	}
+	comma, err := parseDelimiter(delimiter)
+	if err != nil {
+		return err
+	}
+
+	input := io.Reader(os.Stdin)
+	if source != "-" && format != "markdown" {
+		file, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	var newQuotes []quotes.Quote
+	switch format {
+	case "json":
+		newQuotes, err = quotes.ReadJSON(input, source)
+	case "csv":
+		newQuotes, err = quotes.ReadCSV(input, comma, source)
+	case "fortune":
+		newQuotes, err = quotes.ReadFortune(input, isRotatedFortune(source))
+	case "kindle":
+		newQuotes, err = quotes.ReadKindleClippings(input)
+	case "markdown":
+		if source == "-" {
+			newQuotes, err = quotes.ReadMarkdown(input)
+		} else {
+			newQuotes, err = quotes.ReadMarkdownPath(source)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	added, err := quotes.ImportQuotes(newQuotes, dryRun, filePath)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		display.ShowIDs = true
+		renderer, err := newRenderer(display.LayoutWrapped, "", display.TextOutput)
+		if err != nil {
+			return err
+		}
+		renderer.ShowTags = true
+		if err := renderer.RenderList(added); err != nil {
+			return err
+		}
+		fmt.Printf("Would import %d quotes into %s", len(added), filePath)
+	} else {
+		fmt.Printf("Imported %d quotes into %s", len(added), filePath)
+	}
+	if skipped := len(newQuotes) - len(added); skipped > 0 {
+		fmt.Printf(" (%d already there)", skipped)
+	}
+	fmt.Println()
+	return nil
+}
+
+func runExport(filePath string, args []string) error {
+	fs := newFlagSet("export")
+	addFileFlag(fs, &filePath)
+	var format, delimiter string
+	fs.StringVar(&format, "format", "", "Format to write: "+strings.Join(exportFormats, ", ")+" (default from the file name, else json)")
+	fs.StringVar(&delimiter, "delimiter", ",", "Field delimiter for csv, e.g. ';' or '\\t'")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := seedQuotesFile(filePath); err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return usagef("unexpected argument %q", positional[1])
+	}
+
+	destination := "-"
+	if len(positional) == 1 {
+		destination = positional[0]
+	}
+	format, err = transferFormat(format, destination, exportFormats)
+	if err != nil {
+		return err
+	}
+	comma, err := parseDelimiter(delimiter)
+	if err != nil {
+		return err
+	}
+
+	quoteList, err := quotes.LoadQuotesFromFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "json":
+		err = writeJSON(&buf, quoteList)
+	case "csv":
+		err = quotes.WriteCSV(&buf, quoteList, comma)
+	case "fortune":
+		err = quotes.WriteFortune(&buf, quoteList)
+	}
+	if err != nil {
+		return err
+	}
+
+	if destination == "-" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(destination, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if format == "fortune" {
+		// the index fortune needs to pick an entry
+		var index bytes.Buffer
+		quotes.BuildFortuneIndex(buf.Bytes()).WriteTo(&index)
+		if err := os.WriteFile(destination+".dat", index.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d quotes to %s\n", len(quoteList), destination)
+	return nil
+}
+
+// transferFormat checks the --format of import or export against known,
+// defaulting to what the name of path suggests and then to json.
+func transferFormat(format string, path string, known []string) (string, error) {
+	isGuessed := format == ""
+	if isGuessed {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			format = "markdown" // a directory of notes
+		}
+		if strings.EqualFold(filepath.Base(path), "My Clippings.txt") {
+			format = "kindle"
+		}
+	}
+
+	format = strings.ToLower(format)
+	if format == "md" {
+		format = "markdown"
+	}
+	if !slices.Contains(known, format) {
+		if isGuessed {
+			return "json", nil
+		}
+		return "", usagef("unknown format %q, expected one of %s", format, strings.Join(known, ", "))
+	}
+	return format, nil
+}
+
+// isRotatedFortune reports whether the .dat index next to the fortune file
+// at path marks its entries as rot13 encoded.
+func isRotatedFortune(path string) bool {
+	file, err := os.Open(path + ".dat")
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	index, err := quotes.ReadFortuneIndex(file)
+	return err == nil && index.Flags&quotes.FortuneRotated != 0
+}
+
+// parseDelimiter turns the --delimiter flag into a single character, with
+// '\t' or "tab" for a tab.
+func parseDelimiter(delimiter string) (rune, error) {
+	switch delimiter {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	comma, size := utf8.DecodeRuneInString(delimiter)
+	if size == 0 || size != len(delimiter) || comma == utf8.RuneError || comma == '"' || comma == '\r' || comma == '\n' {
+		return 0, usagef("--delimiter must be a single character other than a quote or newline, got %q", delimiter)
+	}
+	return comma, nil
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(v)
+}
@@ -5,6 +5,7 @@ go 1.23.0
 toolchain go1.23.11
 
 require (
-	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/term v0.32.0 // indirect
+	golang.org/x/sys v0.33.0
+	golang.org/x/term v0.32.0
+	golang.org/x/text v0.26.0
 )
@@ -3,6 +3,7 @@ package display
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"unicode/utf8"
@@ -12,18 +13,31 @@ import (
 	"quote-cli/internal/quotes"
 )
 
+// ShowIDs makes every Display function print the quote's ID alongside it.
+var ShowIDs = false
+
 // ====================================================== \\
 //	Internal Helper Functions
 // ====================================================== \\
 
-// getTerminalWidth gets and returns the terminal width, if get
-// fails falls back to 80 rune width.
-func getTerminalWidth() int {
+// terminalFd returns the file descriptor of the terminal w writes to, and
+// false when w is not a terminal.
+func terminalFd(w io.Writer) (int, bool) {
+	file, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return 0, false
+	}
+	fileDescriptor := int(file.Fd())
+	return fileDescriptor, term.IsTerminal(fileDescriptor)
+}
+
+// terminalWidth returns the width of the terminal w writes to, or 80 when w
+// is not a terminal.
+func terminalWidth(w io.Writer) int {
 	fallbackWidth := 80
-	fileDescriptor := int(os.Stdout.Fd()) // Get the file descriptor for standard output
 
-	// Check if os.Stdout is actually connected to a terminal
-	if term.IsTerminal(fileDescriptor) {
+	// Check if w is actually connected to a terminal
+	if fileDescriptor, ok := terminalFd(w); ok {
 		width, _, err := term.GetSize(fileDescriptor) // GetSize returns width, height, error
 		if err != nil {
 			return fallbackWidth
@@ -103,30 +117,29 @@ func basicWrapText(text string, width int) string {
 	return lineReturn
 }
 
-func complexWrapText(text string, width int) string {
+func complexWrapText(text string, width int, theme Theme) string {
 	wrapedLines := wrapText(text, width-10)
 	returnLine := ""
 
 	for i, line := range wrapedLines {
-		fenceLine := ""
-
 		// start fence
+		indent := " "
 		if i == 0 {
-			fenceLine += " |     " + line
-		} else {
-			fenceLine += " | " + line
+			indent = "     "
 		}
 
-		// middle
-		for len(fenceLine) < width-1 {
-			fenceLine += " "
+		// middle, padded by the unstyled length
+		padding := ""
+		for len(" |"+indent+line+padding) < width-1 {
+			padding += " "
 		}
+		fenceLine := theme.Border.Paint(" |") + indent + theme.Text.Paint(line) + padding
 
 		// end fence
 		if i == len(wrapedLines)-1 {
-			fenceLine += " |"
+			fenceLine += theme.Border.Paint(" |")
 		} else {
-			fenceLine += " |\n"
+			fenceLine += theme.Border.Paint(" |") + "\n"
 		}
 
 		returnLine += fenceLine
@@ -139,36 +152,41 @@ func complexWrapText(text string, width int) string {
 //	std Out Display Functions
 // ====================================================== \\
 
+// stdinReader is shared by every prompt so that buffered input (e.g. piped
+// from a script) is not lost between reads.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readLine prints prompt and returns the next line of stdin without its
+// line ending.
+func readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
 // child func of DisplayQuoteAdditionPrompt
 func readQuote() string {
-	newText := ""
-	fmt.Print("Enter your quote: ")
-
-	// read line
-	reader := bufio.NewReader(os.Stdin)
-	newText, err := reader.ReadString('\n')
+	newText, err := readLine("Enter your quote: ")
 	if err != nil {
 		fmt.Println("Error reading quote input:", err)
 		return ""
 	}
-	newText = strings.TrimSuffix(newText, "\n")
 
 	return newText
 }
 
 // child func of DisplayQuoteAdditionPrompt
 func readAuthor() string {
-	author := ""
-	fmt.Print("Enter author name: ")
-
-	// read line
-	reader := bufio.NewReader(os.Stdin)
-	author, err := reader.ReadString('\n')
+	author, err := readLine("Enter author name: ")
 	if err != nil {
 		fmt.Println("Error reading author input:", err)
 		return ""
 	}
-	author = strings.TrimSuffix(author, "\n")
 
 	return author
 }
@@ -176,35 +194,29 @@ func readAuthor() string {
 // child func of DisplayQuoteAdditionPrompt
 func readTags() []string {
 	tags := []string{}
-	noQuite := true
-	for noQuite {
-		newTag := ""
-		fmt.Print("Enter quote tag (type Done to exit): ")
-
-		// read line
-		reader := bufio.NewReader(os.Stdin)
-		newTag, err := reader.ReadString('\n')
+	for {
+		newTag, err := readLine("Enter quote tag (type Done to exit): ")
 		if err != nil {
 			fmt.Println("Error reading quote input:", err)
+			break
 		}
-		newTag = strings.TrimSuffix(newTag, "\n")
 
 		if strings.ToLower(newTag) == "done" {
-			noQuite = false
-		} else {
-			tags = append(tags, newTag)
+			break
 		}
+		tags = append(tags, newTag)
 	}
 
 	return tags
 }
 
-// prompts for the new quote text, author and tags
-func DisplayQuoteAdditionPrompt(filePath string) {
+// prompts for the new quote text, author and tags, and returns the error from
+// saving it
+func DisplayQuoteAdditionPrompt(filePath string) error {
 	newText := readQuote()
 	if len(newText) <= 0 {
 		fmt.Println("No new quote added")
-		return
+		return nil
 	}
 	author := readAuthor()
 	tags := readTags()
@@ -213,55 +225,92 @@ func DisplayQuoteAdditionPrompt(filePath string) {
 
 	// TODO: if finds match allow exit or addition
 
-	// add and catch err
-	err := quotes.AddNewQuote(newText, author, tags, filePath)
+	return quotes.AddNewQuote(newText, author, tags, filePath)
+}
+
+// child func of DisplayQuoteEditPrompt, an empty answer keeps current
+func readWithDefault(label string, current string) (string, error) {
+	answer, err := readLine(fmt.Sprintf("%s [%s]: ", label, current))
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(answer) == "" {
+		return current, nil
+	}
+	return answer, nil
+}
+
+// DisplayQuoteEditPrompt prompts for a new text, author and tags for quote,
+// showing the current values (press enter to keep one), and saves the fields
+// that were changed.
+// Tags are entered comma separated; a single "-" clears them.
+func DisplayQuoteEditPrompt(quote quotes.Quote, filePath string) error {
+	text, err := readWithDefault("Quote text", quote.Text)
+	if err != nil {
+		return fmt.Errorf("reading quote input: %w", err)
+	}
+	author, err := readWithDefault("Author name", quote.Author)
+	if err != nil {
+		return fmt.Errorf("reading author input: %w", err)
+	}
+
+	oldTagLine := strings.Join(quote.Tags, ", ")
+	tagLine, err := readWithDefault("Tags, comma separated (- for none)", oldTagLine)
 	if err != nil {
-		fmt.Println(err)
+		return fmt.Errorf("reading tag input: %w", err)
+	}
+	tags := []string{}
+	if strings.TrimSpace(tagLine) != "-" {
+		for _, tag := range strings.Split(tagLine, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
 	}
+
+	// only what was changed here is saved, over the quote as it is in the
+	// file by now
+	return quotes.EditQuote(quote.ID, func(current *quotes.Quote) {
+		if text != quote.Text {
+			current.Text = text
+		}
+		if author != quote.Author {
+			current.Author = author
+		}
+		if tagLine != oldTagLine {
+			current.Tags = tags
+		}
+	}, filePath)
+}
+
+// DisplayConfirmPrompt asks a yes/no question and reports whether the answer
+// was yes. Anything other than "y" or "yes" (including read errors) is a no.
+func DisplayConfirmPrompt(question string) bool {
+	answer, err := readLine(question + " [y/N]: ")
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
 
 // displayQuoteList prints a list of quotes to the console no fancy formatting.
 func DisplayQuoteListWraped(quoteList []quotes.Quote) {
-	for _, quote := range quoteList {
-		DisplayQuoteWraped(quote)
-	}
+	stdoutRenderer(LayoutWrapped).RenderList(quoteList)
 }
 
 // displayQuote prints the quote to the console no fancy formatting.
 func DisplayQuoteSimple(quote quotes.Quote) {
-	fmt.Printf("%s\n", quote.Text)
-	fmt.Printf("  - %s\n", quote.Author)
+	stdoutRenderer(LayoutSimple).Render(quote)
 }
 
 // DisplayQuoteWraped prints the quote wrapped to the console width.
 func DisplayQuoteWraped(quote quotes.Quote) {
-	terminalWidth := getTerminalWidth()
-
-	// prep then print the quote
-	wrappedQuote := basicWrapText(quote.Text, terminalWidth-4) // Subtract a bit for padding/border
-	fmt.Printf("%s\n", wrappedQuote)
-	fmt.Printf("  - %s\n", quote.Author)
+	stdoutRenderer(LayoutWrapped).Render(quote)
 }
 
 // DisplayQuoteWraped prints the quote wrapped to the console width.
 func DisplayQuoteWrapedBoarder(quote quotes.Quote) {
-	terminalWidth := min(getTerminalWidth(), 90) // keep the quote/boarder from getting to long
-	paddingMargin := 4
-	wrappedQuote := complexWrapText(quote.Text, terminalWidth-paddingMargin) // Subtract a bit for padding/border
-
-	capString := " "
-	for range terminalWidth - paddingMargin {
-		capString += "-"
-	}
-
-	author := quote.Author
-	for len(author) < terminalWidth-paddingMargin-6 {
-		author += " "
-	}
-
-	// prep then print the quote
-	fmt.Printf("%s\n", capString)
-	fmt.Printf("%s\n", wrappedQuote)
-	fmt.Printf(" | - %s\n", author+" |")
-	fmt.Printf("%s\n", capString)
+	stdoutRenderer(LayoutBordered).Render(quote)
 }
@@ -0,0 +1,191 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"quote-cli/internal/quotes"
+)
+
+// ====================================================== \\
+//	Output Formats
+// ====================================================== \\
+
+// TextOutput is the default output: the wrapped, human readable layouts of
+// the Display functions. It is not in the registry, since each command picks
+// its own layout.
+const TextOutput = "text"
+
+// Output writes quotes in one format for other tools to read. isSingle is set
+// when exactly one quote was asked for (a random quote, show), so formats
+// with a list syntax can write the quote on its own.
+type Output func(w io.Writer, quoteList []quotes.Quote, isSingle bool) error
+
+// outputs is the registry of output formats by name.
+var outputs = map[string]Output{}
+
+func init() {
+	RegisterOutput("json", writeJSONOutput)
+	RegisterOutput("ndjson", writeNDJSONOutput)
+	RegisterOutput("csv", writeCSVOutput)
+	RegisterOutput("yaml", writeYAMLOutput)
+	RegisterOutput("markdown", writeMarkdownOutput)
+}
+
+// RegisterOutput makes output available as --output name. It panics if the
+// name is taken, like registering two database drivers under one name.
+func RegisterOutput(name string, output Output) {
+	if _, taken := outputs[name]; taken || name == TextOutput {
+		panic("display: output " + strconv.Quote(name) + " registered twice")
+	}
+	outputs[name] = output
+}
+
+// OutputNames returns the names of every output format, text first and then
+// the registered ones sorted.
+func OutputNames() []string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{TextOutput}, names...)
+}
+
+// LookupOutput returns the registered output format called name.
+func LookupOutput(name string) (Output, bool) {
+	output, ok := outputs[name]
+	return output, ok
+}
+
+// WriteOutput writes quoteList to w in the registered format called name.
+func WriteOutput(w io.Writer, name string, quoteList []quotes.Quote, isSingle bool) error {
+	output, ok := LookupOutput(name)
+	if !ok {
+		return fmt.Errorf("unknown output %q, expected one of %s", name, strings.Join(OutputNames(), ", "))
+	}
+	return output(w, quoteList, isSingle)
+}
+
+// WriteResults writes search results to w in the output called name. The
+// json and ndjson outputs give every quote its relevance "score" next to its
+// other fields; the rest write the quotes alone, as WriteOutput does.
+func WriteResults(w io.Writer, name string, results []quotes.Result) error {
+	switch name {
+	case "json":
+		if results == nil {
+			results = []quotes.Result{} // [] rather than null
+		}
+		return encodeJSON(w, results)
+	case "ndjson":
+		return encodeNDJSON(w, results)
+	}
+	return WriteOutput(w, name, quotes.ResultQuotes(results), false)
+}
+
+// writeJSONOutput writes an indented JSON list, or one object.
+func writeJSONOutput(w io.Writer, quoteList []quotes.Quote, isSingle bool) error {
+	if isSingle && len(quoteList) == 1 {
+		return encodeJSON(w, quoteList[0])
+	}
+	if quoteList == nil {
+		quoteList = []quotes.Quote{} // [] rather than null
+	}
+	return encodeJSON(w, quoteList)
+}
+
+// writeNDJSONOutput writes one JSON object per line.
+func writeNDJSONOutput(w io.Writer, quoteList []quotes.Quote, isSingle bool) error {
+	return encodeNDJSON(w, quoteList)
+}
+
+// encodeJSON writes v as tab indented JSON.
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(v)
+}
+
+// encodeNDJSON writes every item as JSON on a line of its own.
+func encodeNDJSON[T any](w io.Writer, items []T) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCSVOutput writes CSV with a header row, see quotes.WriteCSV.
+func writeCSVOutput(w io.Writer, quoteList []quotes.Quote, isSingle bool) error {
+	return quotes.WriteCSV(w, quoteList, ',')
+}
+
+// writeYAMLOutput writes a YAML list of mappings, or one mapping. Strings are
+// written as JSON strings, which YAML reads as double-quoted scalars.
+func writeYAMLOutput(w io.Writer, quoteList []quotes.Quote, isSingle bool) error {
+	if len(quoteList) == 0 && !isSingle {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+
+	for _, quote := range quoteList {
+		first, rest := "- ", "  "
+		if isSingle {
+			first, rest = "", ""
+		}
+
+		tags := make([]string, len(quote.Tags))
+		for i, tag := range quote.Tags {
+			tags[i] = yamlString(tag)
+		}
+		_, err := fmt.Fprintf(w, "%sid: %d\n%stext: %s\n%sauthor: %s\n%stags: [%s]\n",
+			first, quote.ID,
+			rest, yamlString(quote.Text),
+			rest, yamlString(quote.Author),
+			rest, strings.Join(tags, ", "))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlString quotes s as a YAML double-quoted scalar.
+func yamlString(s string) string {
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// writeMarkdownOutput writes each quote as a blockquote ending in an
+// "— Author" line, which `quote-cli import` reads back.
+func writeMarkdownOutput(w io.Writer, quoteList []quotes.Quote, isSingle bool) error {
+	for i, quote := range quoteList {
+		var sb strings.Builder
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		// each line its own paragraph, so the line breaks survive rendering
+		for j, line := range strings.Split(quote.Text, "\n") {
+			if j > 0 {
+				sb.WriteString(">\n")
+			}
+			sb.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+		if quote.Author != "" {
+			sb.WriteString("> — " + quote.Author + "\n")
+		}
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
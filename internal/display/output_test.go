@@ -0,0 +1,145 @@
+package display
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"quote-cli/internal/quotes"
+)
+
+// TestWriteOutput tests every registered output format for a list and for a
+// single quote.
+func TestWriteOutput(t *testing.T) {
+	sampleQuotes := []quotes.Quote{
+		{ID: 1, Text: "Great work", Author: "Steve Jobs", Tags: []string{"work"}},
+		{ID: 2, Text: "Roses are red,\n\"violets\" <blue>", Author: "", Tags: []string{}},
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		isSingle bool
+		expected string
+	}{
+		{
+			name:     "JSON list",
+			output:   "json",
+			expected: "[\n\t{\n\t\t\"id\": 1,\n\t\t\"text\": \"Great work\",\n\t\t\"author\": \"Steve Jobs\",\n\t\t\"tags\": [\n\t\t\t\"work\"\n\t\t]\n\t},\n\t{\n\t\t\"id\": 2,\n\t\t\"text\": \"Roses are red,\\n\\\"violets\\\" \\u003cblue\\u003e\",\n\t\t\"author\": \"\",\n\t\t\"tags\": []\n\t}\n]\n",
+		},
+		{
+			name:     "JSON single",
+			output:   "json",
+			isSingle: true,
+			expected: "{\n\t\"id\": 1,\n\t\"text\": \"Great work\",\n\t\"author\": \"Steve Jobs\",\n\t\"tags\": [\n\t\t\"work\"\n\t]\n}\n",
+		},
+		{
+			name:   "NDJSON",
+			output: "ndjson",
+			expected: "{\"id\":1,\"text\":\"Great work\",\"author\":\"Steve Jobs\",\"tags\":[\"work\"]}\n" +
+				"{\"id\":2,\"text\":\"Roses are red,\\n\\\"violets\\\" \\u003cblue\\u003e\",\"author\":\"\",\"tags\":[]}\n",
+		},
+		{
+			name:     "CSV",
+			output:   "csv",
+			expected: "id,text,author,tags\n1,Great work,Steve Jobs,work\n2,\"Roses are red,\n\"\"violets\"\" <blue>\",,\n",
+		},
+		{
+			name:   "YAML list",
+			output: "yaml",
+			expected: "- id: 1\n  text: \"Great work\"\n  author: \"Steve Jobs\"\n  tags: [\"work\"]\n" +
+				"- id: 2\n  text: \"Roses are red,\\n\\\"violets\\\" <blue>\"\n  author: \"\"\n  tags: []\n",
+		},
+		{
+			name:     "YAML single",
+			output:   "yaml",
+			isSingle: true,
+			expected: "id: 1\ntext: \"Great work\"\nauthor: \"Steve Jobs\"\ntags: [\"work\"]\n",
+		},
+		{
+			name:     "Markdown",
+			output:   "markdown",
+			expected: "> Great work\n> — Steve Jobs\n\n> Roses are red,\n>\n> \"violets\" <blue>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quoteList := sampleQuotes
+			if tt.isSingle {
+				quoteList = quoteList[:1]
+			}
+
+			var buf bytes.Buffer
+			if err := WriteOutput(&buf, tt.output, quoteList, tt.isSingle); err != nil {
+				t.Fatalf("WriteOutput() returned an unexpected error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("WriteOutput(%q) wrote\n%s\nwant\n%s", tt.output, buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestWriteOutput_Empty tests that list formats write an empty list rather
+// than nothing or null.
+func TestWriteOutput_Empty(t *testing.T) {
+	expected := map[string]string{
+		"json":     "[]\n",
+		"ndjson":   "",
+		"csv":      "id,text,author,tags\n",
+		"yaml":     "[]\n",
+		"markdown": "",
+	}
+	for name, want := range expected {
+		var buf bytes.Buffer
+		if err := WriteOutput(&buf, name, nil, false); err != nil {
+			t.Fatalf("WriteOutput(%q) returned an unexpected error: %v", name, err)
+		}
+		if buf.String() != want {
+			t.Errorf("WriteOutput(%q) of no quotes wrote %q; want %q", name, buf.String(), want)
+		}
+	}
+}
+
+// TestWriteResults tests that the JSON outputs carry the relevance score and
+// the others write the quotes as WriteOutput does.
+func TestWriteResults(t *testing.T) {
+	sampleQuote := quotes.Quote{ID: 1, Text: "Great work", Author: "Steve Jobs", Tags: []string{"work"}}
+	results := []quotes.Result{{Quote: sampleQuote, Score: 1.5}}
+
+	expected := map[string]string{
+		"json":     "[\n\t{\n\t\t\"id\": 1,\n\t\t\"text\": \"Great work\",\n\t\t\"author\": \"Steve Jobs\",\n\t\t\"tags\": [\n\t\t\t\"work\"\n\t\t],\n\t\t\"score\": 1.5\n\t}\n]\n",
+		"ndjson":   "{\"id\":1,\"text\":\"Great work\",\"author\":\"Steve Jobs\",\"tags\":[\"work\"],\"score\":1.5}\n",
+		"csv":      "id,text,author,tags\n1,Great work,Steve Jobs,work\n",
+		"markdown": "> Great work\n> — Steve Jobs\n",
+	}
+	for name, want := range expected {
+		var buf bytes.Buffer
+		if err := WriteResults(&buf, name, results); err != nil {
+			t.Fatalf("WriteResults(%q) returned an unexpected error: %v", name, err)
+		}
+		if buf.String() != want {
+			t.Errorf("WriteResults(%q) wrote\n%s\nwant\n%s", name, buf.String(), want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, "json", nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("WriteResults() of no results wrote %q, %v; want []", buf.String(), err)
+	}
+}
+
+// TestOutputNames tests the registry listing and unknown names.
+func TestOutputNames(t *testing.T) {
+	expected := []string{"text", "csv", "json", "markdown", "ndjson", "yaml"}
+	if got := OutputNames(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("OutputNames() = %v; want %v", got, expected)
+	}
+
+	err := WriteOutput(&bytes.Buffer{}, "xml", nil, false)
+	if err == nil || !strings.Contains(err.Error(), `unknown output "xml"`) {
+		t.Errorf("WriteOutput() of an unknown output error = %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"quote-cli/internal/quotes"
+)
+
+// ====================================================== \\
+//	Renderer
+// ====================================================== \\
+
+// Layout is one of the human readable ways to print a quote.
+type Layout int
+
+const (
+	// LayoutSimple prints the text as is with the author below it.
+	LayoutSimple Layout = iota
+	// LayoutWrapped prints the text quoted and wrapped to the width.
+	LayoutWrapped
+	// LayoutBordered prints the text wrapped inside a box.
+	LayoutBordered
+)
+
+// maxBorderWidth keeps the box of LayoutBordered from getting too wide on
+// wide terminals.
+const maxBorderWidth = 90
+
+// Renderer prints quotes in a Layout, or through a Template, to Out. The
+// Display functions are a Renderer writing to stdout; build your own to print
+// to a file, a buffer or a connection.
+type Renderer struct {
+	Out      io.Writer
+	Width    int // columns to wrap to
+	Layout   Layout
+	ShowIDs  bool  // print the quote's ID alongside it
+	ShowTags bool  // print the quote's tags below it
+	Theme    Theme // colors, the zero Theme for plain text
+
+	// Template, when set, replaces Layout; see ParseTemplate.
+	Template *template.Template
+}
+
+// NewRenderer returns a Renderer writing to out in layout, as wide as out if
+// it is a terminal and 80 columns otherwise.
+func NewRenderer(out io.Writer, layout Layout) *Renderer {
+	return &Renderer{Out: out, Width: terminalWidth(out), Layout: layout}
+}
+
+// stdoutRenderer is the Renderer behind the Display functions.
+func stdoutRenderer(layout Layout) *Renderer {
+	renderer := NewRenderer(os.Stdout, layout)
+	renderer.ShowIDs = ShowIDs
+	renderer.Theme = ColorTheme
+	return renderer
+}
+
+// Render prints quote.
+func (r *Renderer) Render(quote quotes.Quote) error {
+	var text string
+	switch {
+	case r.Template != nil:
+		var err error
+		if text, err = r.renderTemplate(quote); err != nil {
+			return err
+		}
+	case r.Layout == LayoutWrapped:
+		text = r.renderWrapped(quote)
+	case r.Layout == LayoutBordered:
+		text = r.renderBordered(quote)
+	default:
+		text = r.renderSimple(quote)
+	}
+
+	_, err := io.WriteString(r.Out, text)
+	return err
+}
+
+// RenderList prints every quote in quoteList, stopping at the first error.
+func (r *Renderer) RenderList(quoteList []quotes.Quote) error {
+	for _, quote := range quoteList {
+		if err := r.Render(quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// idPrefix returns "[id] " when ShowIDs is set, otherwise "".
+func (r *Renderer) idPrefix(quote quotes.Quote) string {
+	if !r.ShowIDs {
+		return ""
+	}
+	return fmt.Sprintf("[%d] ", quote.ID)
+}
+
+// tagLine returns the "tags: a, b" line, or "" when tags aren't shown.
+func (r *Renderer) tagLine(quote quotes.Quote) string {
+	if !r.ShowTags || len(quote.Tags) == 0 {
+		return ""
+	}
+	return "tags: " + strings.Join(quote.Tags, ", ")
+}
+
+// paintLines paints every line of text on its own, so a pager showing part
+// of a quote keeps its style.
+func paintLines(style Style, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = style.Paint(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Renderer) renderSimple(quote quotes.Quote) string {
+	return r.renderAuthorBelow(quote, quote.Text)
+}
+
+func (r *Renderer) renderWrapped(quote quotes.Quote) string {
+	wrappedQuote := basicWrapText(quote.Text, r.Width-4) // Subtract a bit for padding/border
+	return r.renderAuthorBelow(quote, wrappedQuote)
+}
+
+// renderAuthorBelow prints text with the author, and the tags, below it.
+func (r *Renderer) renderAuthorBelow(quote quotes.Quote, text string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s%s\n", r.idPrefix(quote), paintLines(r.Theme.Text, text))
+	fmt.Fprintf(&sb, "  - %s\n", r.Theme.Author.Paint(quote.Author))
+	if tags := r.tagLine(quote); tags != "" {
+		fmt.Fprintf(&sb, "    %s\n", r.Theme.Tags.Paint(tags))
+	}
+	return sb.String()
+}
+
+func (r *Renderer) renderBordered(quote quotes.Quote) string {
+	width := min(r.Width, maxBorderWidth)
+	paddingMargin := 4
+	wrappedQuote := complexWrapText(quote.Text, width-paddingMargin, r.Theme) // Subtract a bit for padding/border
+
+	capString := " " + r.Theme.Border.Paint(strings.Repeat("-", max(width-paddingMargin, 0)))
+	fence := r.Theme.Border.Paint(" |")
+
+	padding := ""
+	id := strings.TrimSpace(r.idPrefix(quote))
+	for len(quote.Author+padding) < width-paddingMargin-6-len(id) {
+		padding += " "
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", capString)
+	fmt.Fprintf(&sb, "%s\n", wrappedQuote)
+	fmt.Fprintf(&sb, "%s - %s%s%s%s\n", fence, r.Theme.Author.Paint(quote.Author), padding, id, fence)
+	if tags := r.tagLine(quote); tags != "" {
+		for _, line := range wrapText(tags, width-paddingMargin-10) {
+			padding := ""
+			for len(" |   "+line+padding) < width-paddingMargin-1 {
+				padding += " "
+			}
+			fmt.Fprintf(&sb, "%s   %s%s%s\n", fence, r.Theme.Tags.Paint(line), padding, fence)
+		}
+	}
+	fmt.Fprintf(&sb, "%s\n", capString)
+	return sb.String()
+}
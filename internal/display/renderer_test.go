@@ -0,0 +1,173 @@
+package display
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"quote-cli/internal/quotes"
+)
+
+// TestRender tests the output of every layout against golden text.
+func TestRender(t *testing.T) {
+	quote := quotes.Quote{ID: 7, Text: "The only way to do great work is to love what you do.", Author: "Steve Jobs"}
+
+	tests := []struct {
+		name     string
+		layout   Layout
+		width    int
+		showIDs  bool
+		expected string
+	}{
+		{
+			name:   "Simple",
+			layout: LayoutSimple,
+			width:  40,
+			expected: `The only way to do great work is to love what you do.
+  - Steve Jobs
+`,
+		},
+		{
+			name:    "Simple with ID",
+			layout:  LayoutSimple,
+			width:   40,
+			showIDs: true,
+			expected: `[7] The only way to do great work is to love what you do.
+  - Steve Jobs
+`,
+		},
+		{
+			name:   "Wrapped",
+			layout: LayoutWrapped,
+			width:  40,
+			expected: `"The only way to do great work is to
+love what you do."
+  - Steve Jobs
+`,
+		},
+		{
+			name:   "Bordered",
+			layout: LayoutBordered,
+			width:  40,
+			expected: ` ------------------------------------
+ |     The only way to do great     |
+ | work is to love what you         |
+ | do.                              |
+ | - Steve Jobs                     |
+ ------------------------------------
+`,
+		},
+		{
+			name:    "Bordered with ID",
+			layout:  LayoutBordered,
+			width:   40,
+			showIDs: true,
+			expected: ` ------------------------------------
+ |     The only way to do great     |
+ | work is to love what you         |
+ | do.                              |
+ | - Steve Jobs                 [7] |
+ ------------------------------------
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			renderer := &Renderer{Out: &buf, Width: tt.width, Layout: tt.layout, ShowIDs: tt.showIDs}
+			if err := renderer.Render(quote); err != nil {
+				t.Fatalf("Render() returned an unexpected error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Render() wrote\n%s\nwant\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestRenderTags tests the tags line of each layout.
+func TestRenderTags(t *testing.T) {
+	quote := quotes.Quote{Text: "Great work", Author: "Steve Jobs", Tags: []string{"work", "passion"}}
+
+	tests := []struct {
+		layout   Layout
+		expected string
+	}{
+		{
+			layout: LayoutSimple,
+			expected: `Great work
+  - Steve Jobs
+    tags: work, passion
+`,
+		},
+		{
+			layout: LayoutBordered,
+			expected: ` ------------------------------------
+ |     Great work                   |
+ | - Steve Jobs                     |
+ |   tags: work, passion            |
+ ------------------------------------
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		renderer := &Renderer{Out: &buf, Width: 40, Layout: tt.layout, ShowTags: true}
+		if err := renderer.Render(quote); err != nil {
+			t.Fatalf("Render() returned an unexpected error: %v", err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("Render() wrote\n%s\nwant\n%s", buf.String(), tt.expected)
+		}
+	}
+}
+
+// TestRenderBorderedMaxWidth tests that the box stops growing past
+// maxBorderWidth on wide terminals.
+func TestRenderBorderedMaxWidth(t *testing.T) {
+	var narrow, wide bytes.Buffer
+	quote := quotes.Quote{Text: "Be yourself", Author: "Oscar Wilde"}
+	(&Renderer{Out: &narrow, Width: maxBorderWidth, Layout: LayoutBordered}).Render(quote)
+	(&Renderer{Out: &wide, Width: 200, Layout: LayoutBordered}).Render(quote)
+	if narrow.String() != wide.String() {
+		t.Errorf("Render() at width 200 wrote\n%s\nwant\n%s", wide.String(), narrow.String())
+	}
+}
+
+// TestRenderList tests that a list renders quote after quote.
+func TestRenderList(t *testing.T) {
+	var buf bytes.Buffer
+	renderer := &Renderer{Out: &buf, Width: 80, Layout: LayoutSimple}
+	quoteList := []quotes.Quote{
+		{Text: "Great work", Author: "Steve Jobs"},
+		{Text: "Be yourself", Author: "Oscar Wilde"},
+	}
+	if err := renderer.RenderList(quoteList); err != nil {
+		t.Fatalf("RenderList() returned an unexpected error: %v", err)
+	}
+
+	expected := "Great work\n  - Steve Jobs\nBe yourself\n  - Oscar Wilde\n"
+	if buf.String() != expected {
+		t.Errorf("RenderList() wrote %q; want %q", buf.String(), expected)
+	}
+}
+
+// failingWriter fails every write, like a closed connection.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+// TestRenderWriteError tests that write errors are returned.
+func TestRenderWriteError(t *testing.T) {
+	renderer := NewRenderer(failingWriter{}, LayoutWrapped)
+	if renderer.Width != 80 {
+		t.Errorf("NewRenderer() of a non terminal Width = %d; want 80", renderer.Width)
+	}
+	if err := renderer.RenderList([]quotes.Quote{{Text: "a"}, {Text: "b"}}); err == nil {
+		t.Error("RenderList() to a failing writer returned no error")
+	}
+}
@@ -0,0 +1,130 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"quote-cli/internal/quotes"
+)
+
+// ====================================================== \\
+//	Templates
+// ====================================================== \\
+
+// TemplateDir is where LoadTemplate looks for named templates, one
+// <name>.tmpl file each. Empty means only the built-in templates.
+var TemplateDir = ""
+
+// templateExt is the extension of a named template file.
+const templateExt = ".tmpl"
+
+// builtinTemplates are the named templates available without any files. A
+// file in TemplateDir with the same name replaces one.
+var builtinTemplates = map[string]string{
+	"oneline": "{{.Text}} — {{.Author}}",
+	"slack":   "{{range wrap 0 .Text}}> {{.}}\n{{end}}> — _{{.Author}}_",
+}
+
+// TemplateData is what a template is executed with: the quote's fields
+// (.ID, .Text, .Author, .Tags) plus its text wrapped to the renderer's width.
+type TemplateData struct {
+	quotes.Quote
+	Lines []string // Text wrapped to Width, keeping its own line breaks
+	Width int
+}
+
+// templateFuncs are the functions templates can call besides the text/template
+// builtins. style is replaced by the renderer's theme when executing.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"wrap":  wrapLines,
+	"style": Theme{}.style,
+}
+
+// wrapLines wraps every line of text to width, so a quote's own line breaks
+// survive. A width of 0 or less only splits the lines.
+func wrapLines(width int, text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		wrapped := wrapText(line, width)
+		if len(wrapped) == 0 {
+			wrapped = []string{""}
+		}
+		lines = append(lines, wrapped...)
+	}
+	return lines
+}
+
+// ParseTemplate parses text as a quote template called name, with the
+// functions join, upper, lower, wrap and style ({{style "author" .Author}})
+// available. It is executed with a TemplateData.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// LoadTemplate returns the template for a --format value: the value itself
+// when it holds an action ("{{"), otherwise the named template from
+// TemplateDir or the built-in ones.
+func LoadTemplate(format string) (*template.Template, error) {
+	if strings.Contains(format, "{{") {
+		return ParseTemplate("format", format)
+	}
+
+	name := format
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	if TemplateDir != "" {
+		data, err := os.ReadFile(filepath.Join(TemplateDir, name+templateExt))
+		if err == nil {
+			return ParseTemplate(name, strings.TrimSuffix(string(data), "\n"))
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading template %q: %w", name, err)
+		}
+	}
+
+	if text, ok := builtinTemplates[name]; ok {
+		return ParseTemplate(name, text)
+	}
+	return nil, fmt.Errorf("unknown template %q, expected one of %s", name, strings.Join(TemplateNames(), ", "))
+}
+
+// TemplateNames returns the names of the built-in templates and of those in
+// TemplateDir, sorted.
+func TemplateNames() []string {
+	builtin := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		builtin = append(builtin, name)
+	}
+	return namedFiles(builtin, TemplateDir, templateExt)
+}
+
+// renderTemplate executes the renderer's template for quote. Every quote ends
+// in a newline, so templates don't need one.
+func (r *Renderer) renderTemplate(quote quotes.Quote) (string, error) {
+	data := TemplateData{Quote: quote, Lines: wrapLines(r.Width, quote.Text), Width: r.Width}
+
+	tmpl, err := r.Template.Clone()
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(template.FuncMap{"style": r.Theme.style})
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,142 @@
+package display
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"quote-cli/internal/quotes"
+)
+
+// TestRenderTemplate tests printing a quote through a template.
+func TestRenderTemplate(t *testing.T) {
+	quote := quotes.Quote{ID: 3, Text: "Roses are red,\nviolets are blue", Author: "Anon", Tags: []string{"poem", "flowers"}}
+
+	tests := []struct {
+		name     string
+		format   string
+		width    int
+		expected string
+	}{
+		{
+			name:     "Fields",
+			format:   "{{.Text}} — {{.Author}}",
+			expected: "Roses are red,\nviolets are blue — Anon\n",
+		},
+		{
+			name:     "ID and tags",
+			format:   "#{{.ID}} {{join .Tags \",\"}}",
+			expected: "#3 poem,flowers\n",
+		},
+		{
+			name:     "Wrapped lines",
+			format:   "{{range .Lines}}| {{.}}\n{{end}}",
+			width:    10,
+			expected: "| Roses are\n| red,\n| violets\n| are blue\n",
+		},
+		{
+			name:     "Functions",
+			format:   "{{upper .Author}} {{lower (index (wrap 0 .Text) 1)}}",
+			expected: "ANON violets are blue\n",
+		},
+		{
+			name:     "Ending newline kept",
+			format:   "{{.Author}}\n",
+			expected: "Anon\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.name, tt.format)
+			if err != nil {
+				t.Fatalf("ParseTemplate() returned an unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			renderer := &Renderer{Out: &buf, Width: tt.width, Template: tmpl}
+			if err := renderer.Render(quote); err != nil {
+				t.Fatalf("Render() returned an unexpected error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Render() wrote %q; want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestTemplate_Errors tests that broken templates fail to parse, and that
+// unknown fields fail to render.
+func TestTemplate_Errors(t *testing.T) {
+	for _, format := range []string{"{{.Text", "{{nofunc .Text}}"} {
+		if _, err := ParseTemplate("test", format); err == nil {
+			t.Errorf("ParseTemplate(%q) returned no error", format)
+		}
+	}
+
+	tmpl, err := ParseTemplate("test", "{{.Missing}}")
+	if err != nil {
+		t.Fatalf("ParseTemplate() returned an unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (&Renderer{Out: &buf, Template: tmpl}).Render(quotes.Quote{}); err == nil || buf.Len() != 0 {
+		t.Errorf("Render() of an unknown field = %v, wrote %q; want an error and nothing written", err, buf.String())
+	}
+}
+
+// TestLoadTemplate tests inline, named and built-in templates.
+func TestLoadTemplate(t *testing.T) {
+	TemplateDir = t.TempDir()
+	t.Cleanup(func() { TemplateDir = "" })
+	files := map[string]string{
+		"prompt.tmpl":  "[{{.ID}}] {{.Author}}\n",
+		"oneline.tmpl": "{{.Author}} said {{.Text}}",
+		"notes.txt":    "not a template",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(TemplateDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test template: %v", err)
+		}
+	}
+
+	quote := quotes.Quote{ID: 1, Text: "Be yourself", Author: "Oscar Wilde"}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "{{.Text}}!", expected: "Be yourself!\n"},
+		{format: "prompt", expected: "[1] Oscar Wilde\n"},
+		{format: "oneline", expected: "Oscar Wilde said Be yourself\n"}, // the file replaces the built-in
+		{format: "slack", expected: "> Be yourself\n> — _Oscar Wilde_\n"},
+	}
+	for _, tt := range tests {
+		tmpl, err := LoadTemplate(tt.format)
+		if err != nil {
+			t.Fatalf("LoadTemplate(%q) returned an unexpected error: %v", tt.format, err)
+		}
+		var buf bytes.Buffer
+		if err := (&Renderer{Out: &buf, Template: tmpl}).Render(quote); err != nil {
+			t.Fatalf("Render() returned an unexpected error: %v", err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("LoadTemplate(%q) rendered %q; want %q", tt.format, buf.String(), tt.expected)
+		}
+	}
+
+	for _, format := range []string{"notes", "../prompt", ""} {
+		if _, err := LoadTemplate(format); err == nil {
+			t.Errorf("LoadTemplate(%q) returned no error", format)
+		}
+	}
+	_, err := LoadTemplate("missing")
+	if err == nil || !strings.Contains(err.Error(), "oneline, prompt, slack") {
+		t.Errorf("LoadTemplate() of an unknown name error = %v; want the template names", err)
+	}
+
+	if got, expected := TemplateNames(), []string{"oneline", "prompt", "slack"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("TemplateNames() = %v; want %v", got, expected)
+	}
+}
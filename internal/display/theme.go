@@ -0,0 +1,250 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ====================================================== \\
+//	Color Themes
+// ====================================================== \\
+
+// ColorTheme styles every Display function's output. The zero Theme prints
+// plain text.
+var ColorTheme = Theme{}
+
+// ThemeDir is where LoadTheme looks for user themes, one <name>.json file
+// each. Empty means only the built-in themes.
+var ThemeDir = ""
+
+// DefaultTheme is the theme used unless another is chosen.
+const DefaultTheme = "default"
+
+// themeExt is the extension of a user theme file.
+const themeExt = ".json"
+
+// Style is a list of ANSI SGR parameters, e.g. "1;36" for bold cyan. The zero
+// Style prints text unchanged.
+type Style string
+
+// styleWords are the attribute and color words ParseStyle understands.
+// "bright-<color>" and "on-<color>" are derived from the colors.
+var styleWords = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+}
+
+// ParseStyle parses a space separated list of style words: bold, dim, italic,
+// underline, a color (black, red, green, yellow, blue, magenta, cyan, white),
+// bright-<color>, on-<color> for the background, or a number from the 256
+// color palette. An empty spec is the plain Style.
+func ParseStyle(spec string) (Style, error) {
+	var params []string
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		param, err := styleParam(word)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, param)
+	}
+	return Style(strings.Join(params, ";")), nil
+}
+
+// styleParam returns the SGR parameter for one style word.
+func styleParam(word string) (string, error) {
+	if param, ok := styleWords[word]; ok {
+		return param, nil
+	}
+	if color, ok := strings.CutPrefix(word, "bright-"); ok && isColorCode(styleWords[color]) {
+		code, _ := strconv.Atoi(styleWords[color])
+		return strconv.Itoa(code + 60), nil
+	}
+	if color, ok := strings.CutPrefix(word, "on-"); ok && isColorCode(styleWords[color]) {
+		code, _ := strconv.Atoi(styleWords[color])
+		return strconv.Itoa(code + 10), nil
+	}
+	if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+		return "38;5;" + word, nil
+	}
+	return "", fmt.Errorf("unknown style %q", word)
+}
+
+// isColorCode reports whether param is one of the eight foreground colors.
+func isColorCode(param string) bool {
+	return len(param) == 2 && param[0] == '3'
+}
+
+// UnmarshalText parses a Style from its words, so themes are written as
+// {"author": "bold cyan"}.
+func (s *Style) UnmarshalText(text []byte) error {
+	style, err := ParseStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*s = style
+	return nil
+}
+
+// Paint wraps text in the escape codes of s.
+func (s Style) Paint(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(s) + "m" + text + "\x1b[0m"
+}
+
+// Theme is the Style of each part of a printed quote.
+type Theme struct {
+	Text   Style `json:"text"`
+	Author Style `json:"author"`
+	Tags   Style `json:"tags"`
+	Border Style `json:"border"`
+}
+
+// part returns the Style of the part called name, for the style template
+// function.
+func (t Theme) part(name string) (Style, error) {
+	switch name {
+	case "text":
+		return t.Text, nil
+	case "author":
+		return t.Author, nil
+	case "tags":
+		return t.Tags, nil
+	case "border":
+		return t.Border, nil
+	}
+	return "", fmt.Errorf("unknown style %q, expected text, author, tags or border", name)
+}
+
+// style paints text in the Style of the part called name.
+func (t Theme) style(name, text string) (string, error) {
+	style, err := t.part(name)
+	if err != nil {
+		return "", err
+	}
+	return style.Paint(text), nil
+}
+
+// builtinThemes are the themes available without any files. A file in
+// ThemeDir with the same name replaces one.
+var builtinThemes = map[string]Theme{
+	// italic text, bold cyan author, dim tags, blue border
+	"default": {Text: "3", Author: "1;36", Tags: "2", Border: "34"},
+	// bold author, dim tags and border
+	"mono": {Author: "1", Tags: "2", Border: "2"},
+	// yellow text, bold red author, dim yellow tags, red border
+	"warm": {Text: "33", Author: "1;31", Tags: "2;33", Border: "31"},
+}
+
+// LoadTheme returns the theme called name from ThemeDir, or the built-in one.
+func LoadTheme(name string) (Theme, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return Theme{}, fmt.Errorf("invalid theme name %q", name)
+	}
+
+	if ThemeDir != "" {
+		file, err := os.Open(filepath.Join(ThemeDir, name+themeExt))
+		if err == nil {
+			defer file.Close()
+			return readTheme(file, name)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return Theme{}, fmt.Errorf("reading theme %q: %w", name, err)
+		}
+	}
+
+	if theme, ok := builtinThemes[name]; ok {
+		return theme, nil
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(ThemeNames(), ", "))
+}
+
+// readTheme decodes a theme file. Unknown keys are an error, so a typo does
+// not silently leave a part plain.
+func readTheme(r io.Reader, name string) (Theme, error) {
+	var theme Theme
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&theme); err != nil {
+		return Theme{}, fmt.Errorf("theme %q: %w", name, err)
+	}
+	return theme, nil
+}
+
+// ThemeNames returns the names of the built-in themes and of those in
+// ThemeDir, sorted.
+func ThemeNames() []string {
+	builtin := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		builtin = append(builtin, name)
+	}
+	return namedFiles(builtin, ThemeDir, themeExt)
+}
+
+// namedFiles returns builtin plus the names of the ext files in dir, sorted
+// and without repeats.
+func namedFiles(builtin []string, dir, ext string) []string {
+	seen := map[string]bool{}
+	for _, name := range builtin {
+		seen[name] = true
+	}
+	if dir != "" {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if !entry.IsDir() && filepath.Ext(entry.Name()) == ext {
+				seen[strings.TrimSuffix(entry.Name(), ext)] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values of --color.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// UseColor reports whether output to w is colored in mode: always, never, or
+// auto, which colors only a terminal and respects NO_COLOR (https://no-color.org).
+func UseColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case ColorAlways:
+		return true, nil
+	case ColorNever:
+		return false, nil
+	case ColorAuto:
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		_, ok := terminalFd(w)
+		return ok, nil
+	}
+	return false, fmt.Errorf("unknown color mode %q, expected %s, %s or %s", mode, ColorAlways, ColorNever, ColorAuto)
+}
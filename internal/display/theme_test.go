@@ -0,0 +1,178 @@
+package display
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"quote-cli/internal/quotes"
+)
+
+// TestParseStyle tests turning style words into SGR parameters.
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Style
+		isError  bool
+	}{
+		{spec: "", expected: ""},
+		{spec: "bold cyan", expected: "1;36"},
+		{spec: "Italic  bright-red on-blue", expected: "3;91;44"},
+		{spec: "208", expected: "38;5;208"},
+		{spec: "bright-bold", isError: true},
+		{spec: "256", isError: true},
+		{spec: "purple", isError: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStyle(tt.spec)
+		if (err != nil) != tt.isError {
+			t.Errorf("ParseStyle(%q) error = %v; want error %v", tt.spec, err, tt.isError)
+		}
+		if got != tt.expected {
+			t.Errorf("ParseStyle(%q) = %q; want %q", tt.spec, got, tt.expected)
+		}
+	}
+}
+
+// TestPaint tests that a style wraps text and the plain style doesn't.
+func TestPaint(t *testing.T) {
+	if got := Style("1;36").Paint("Seneca"); got != "\x1b[1;36mSeneca\x1b[0m" {
+		t.Errorf("Paint() = %q", got)
+	}
+	if got := Style("").Paint("Seneca"); got != "Seneca" {
+		t.Errorf("Paint() of the plain style = %q", got)
+	}
+	if got := Style("1").Paint(""); got != "" {
+		t.Errorf("Paint() of no text = %q", got)
+	}
+}
+
+// TestLoadTheme tests user themes, the built-in ones and bad theme files.
+func TestLoadTheme(t *testing.T) {
+	ThemeDir = t.TempDir()
+	t.Cleanup(func() { ThemeDir = "" })
+	files := map[string]string{
+		"mine.json":  `{"text": "italic", "author": "bold green"}`,
+		"mono.json":  `{"border": "red"}`,
+		"typo.json":  `{"autor": "red"}`,
+		"color.json": `{"text": "purple"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(ThemeDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test theme: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expected Theme
+	}{
+		{name: "mine", expected: Theme{Text: "3", Author: "1;32"}},
+		{name: "mono", expected: Theme{Border: "31"}}, // the file replaces the built-in
+		{name: "warm", expected: builtinThemes["warm"]},
+	}
+	for _, tt := range tests {
+		got, err := LoadTheme(tt.name)
+		if err != nil {
+			t.Fatalf("LoadTheme(%q) returned an unexpected error: %v", tt.name, err)
+		}
+		if got != tt.expected {
+			t.Errorf("LoadTheme(%q) = %+v; want %+v", tt.name, got, tt.expected)
+		}
+	}
+
+	for _, name := range []string{"typo", "color", "missing", "../mine", ""} {
+		if _, err := LoadTheme(name); err == nil {
+			t.Errorf("LoadTheme(%q) returned no error", name)
+		}
+	}
+
+	expected := "color, default, mine, mono, typo, warm"
+	if got := strings.Join(ThemeNames(), ", "); got != expected {
+		t.Errorf("ThemeNames() = %s; want %s", got, expected)
+	}
+}
+
+// TestUseColor tests the --color modes and NO_COLOR.
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+	tests := []struct {
+		mode     string
+		noColor  string
+		expected bool
+	}{
+		{mode: ColorAlways, expected: true},
+		{mode: ColorAlways, noColor: "1", expected: true},
+		{mode: ColorNever, expected: false},
+		{mode: ColorAuto, expected: false}, // a buffer is not a terminal
+		{mode: ColorAuto, noColor: "1", expected: false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		got, err := UseColor(tt.mode, &buf)
+		if err != nil {
+			t.Fatalf("UseColor(%q) returned an unexpected error: %v", tt.mode, err)
+		}
+		if got != tt.expected {
+			t.Errorf("UseColor(%q) with NO_COLOR=%q = %v; want %v", tt.mode, tt.noColor, got, tt.expected)
+		}
+	}
+
+	if _, err := UseColor("sometimes", &buf); err == nil {
+		t.Error("UseColor() of an unknown mode returned no error")
+	}
+}
+
+// ansiCodes matches the escape codes Paint adds.
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// TestRenderTheme tests that themes color each part, and that removing the
+// colors gives back the plain layout.
+func TestRenderTheme(t *testing.T) {
+	theme := Theme{Text: "3", Author: "1", Tags: "2", Border: "34"}
+	quote := quotes.Quote{ID: 4, Text: "Be yourself; everyone else is already taken.", Author: "Oscar Wilde", Tags: []string{"life"}}
+
+	var buf bytes.Buffer
+	(&Renderer{Out: &buf, Width: 30, Layout: LayoutWrapped, ShowTags: true, Theme: theme}).Render(quote)
+	expected := "\x1b[3m\"Be yourself; everyone else\x1b[0m\n\x1b[3mis already taken.\"\x1b[0m\n  - \x1b[1mOscar Wilde\x1b[0m\n    \x1b[2mtags: life\x1b[0m\n"
+	if buf.String() != expected {
+		t.Errorf("Render() wrote %q; want %q", buf.String(), expected)
+	}
+
+	for _, layout := range []Layout{LayoutSimple, LayoutWrapped, LayoutBordered} {
+		var colored, plain bytes.Buffer
+		(&Renderer{Out: &colored, Width: 40, Layout: layout, ShowIDs: true, ShowTags: true, Theme: theme}).Render(quote)
+		(&Renderer{Out: &plain, Width: 40, Layout: layout, ShowIDs: true, ShowTags: true}).Render(quote)
+		if got := ansiCodes.ReplaceAllString(colored.String(), ""); got != plain.String() {
+			t.Errorf("layout %d without colors wrote\n%s\nwant\n%s", layout, got, plain.String())
+		}
+	}
+}
+
+// TestTemplateStyle tests the style template function.
+func TestTemplateStyle(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `{{style "author" .Author}}: {{.Text}}`)
+	if err != nil {
+		t.Fatalf("ParseTemplate() returned an unexpected error: %v", err)
+	}
+	quote := quotes.Quote{Text: "To be", Author: "Shakespeare"}
+
+	var plain, colored bytes.Buffer
+	(&Renderer{Out: &plain, Template: tmpl}).Render(quote)
+	(&Renderer{Out: &colored, Template: tmpl, Theme: Theme{Author: "1"}}).Render(quote)
+	if plain.String() != "Shakespeare: To be\n" {
+		t.Errorf("Render() without a theme wrote %q", plain.String())
+	}
+	if colored.String() != "\x1b[1mShakespeare\x1b[0m: To be\n" {
+		t.Errorf("Render() with a theme wrote %q", colored.String())
+	}
+
+	tmpl, _ = ParseTemplate("test", `{{style "quote" .Text}}`)
+	if err := (&Renderer{Out: &plain, Template: tmpl}).Render(quote); err == nil {
+		t.Error("Render() of an unknown style returned no error")
+	}
+}
@@ -0,0 +1,79 @@
+package quotes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ====================================================== \\
+//	Atomic File Writes
+// ====================================================== \\
+
+// writeFileAtomic replaces the contents of filePath with data so that readers
+// (and a crash) only ever see the old file or the new one, never a partly
+// written one. data goes to a temp file in the same directory, is synced to
+// disk and then renamed over filePath.
+//
+// An existing file keeps its permission bits; a new one gets perm. If
+// filePath is a symlink the file it points to is replaced, not the link.
+func writeFileAtomic(filePath string, data []byte, perm fs.FileMode) (err error) {
+	if target, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = target
+	}
+	if info, err := os.Stat(filePath); err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	dir, name := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+
+	// same directory, so the rename never crosses file systems
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to replace %q: %w", filePath, err)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes dir so a rename into it survives a crash. It is best effort:
+// some systems (Windows) cannot open or sync directories, and the data itself
+// is already safe by then.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,120 @@
+package quotes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tempFiles returns the names of the leftover temp files in dir.
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	if err != nil {
+		t.Fatalf("failed to list temp files: %v", err)
+	}
+	return matches
+}
+
+// TestWriteFileAtomic tests replacing files without leaving temp files behind.
+func TestWriteFileAtomic(t *testing.T) {
+	tests := []struct {
+		name         string
+		existingMode os.FileMode // 0 for no existing file
+		expectedMode os.FileMode
+	}{
+		{name: "New file gets the default mode", expectedMode: 0644},
+		{name: "Existing file keeps its mode", existingMode: 0600, expectedMode: 0600},
+		{name: "Existing group writable file", existingMode: 0664, expectedMode: 0664},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			testFilePath := filepath.Join(dir, "quotes.json")
+			if tt.existingMode != 0 {
+				if err := os.WriteFile(testFilePath, []byte("old"), tt.existingMode); err != nil {
+					t.Fatalf("failed to write test file: %v", err)
+				}
+				// WriteFile's mode is filtered by the umask
+				if err := os.Chmod(testFilePath, tt.existingMode); err != nil {
+					t.Fatalf("failed to chmod test file: %v", err)
+				}
+			}
+
+			if err := writeFileAtomic(testFilePath, []byte("new"), 0644); err != nil {
+				t.Fatalf("writeFileAtomic() returned an unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(testFilePath)
+			if err != nil {
+				t.Fatalf("failed to read test file: %v", err)
+			}
+			if string(data) != "new" {
+				t.Errorf("file contains %q; want %q", data, "new")
+			}
+			info, err := os.Stat(testFilePath)
+			if err != nil {
+				t.Fatalf("failed to stat test file: %v", err)
+			}
+			if info.Mode().Perm() != tt.expectedMode {
+				t.Errorf("file mode = %v; want %v", info.Mode().Perm(), tt.expectedMode)
+			}
+			if leftover := tempFiles(t, dir); len(leftover) > 0 {
+				t.Errorf("temp files left behind: %v", leftover)
+			}
+		})
+	}
+}
+
+// TestWriteFileAtomic_Symlink tests that a symlinked file is replaced through
+// the link, keeping the link.
+func TestWriteFileAtomic_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real.json")
+	link := filepath.Join(dir, "default.json")
+	if err := os.WriteFile(target, []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := writeFileAtomic(link, []byte("new"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic() returned an unexpected error: %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlink was replaced by a file")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("link target contains %q; want %q", data, "new")
+	}
+}
+
+// TestWriteFileAtomic_Failure tests that a failed write leaves nothing behind
+// and the original untouched.
+func TestWriteFileAtomic_Failure(t *testing.T) {
+	dir := t.TempDir()
+
+	// a directory cannot be replaced by a file, so the rename fails
+	testFilePath := filepath.Join(dir, "quotes.json")
+	if err := os.Mkdir(testFilePath, 0755); err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+
+	if err := writeFileAtomic(testFilePath, []byte("new"), 0644); err == nil {
+		t.Fatal("writeFileAtomic() over a directory returned no error")
+	}
+	if info, err := os.Stat(testFilePath); err != nil || !info.IsDir() {
+		t.Errorf("original was changed")
+	}
+	if leftover := tempFiles(t, dir); len(leftover) > 0 {
+		t.Errorf("temp files left behind: %v", leftover)
+	}
+
+	// a missing directory fails before anything is written
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "quotes.json"), []byte("new"), 0644); err == nil {
+		t.Error("writeFileAtomic() into a missing directory returned no error")
+	}
+}
@@ -0,0 +1,306 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ====================================================== \\
+//	Backups
+// ====================================================== \\
+
+// BackupCount is how many backups of a quotes file are kept. Before every
+// write the current file is copied into its backup directory (see BackupDir)
+// and the oldest backups beyond BackupCount are deleted. Zero turns backups
+// off.
+var BackupCount = 10
+
+// backupTimeFormat names backup files so they sort oldest to newest.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// Backup is one saved copy of a quotes file.
+type Backup struct {
+	Path string
+	Time time.Time // when the copy was taken, in UTC
+}
+
+// Name is the backup's file name, which identifies it to RestoreBackup.
+func (b Backup) Name() string {
+	return filepath.Base(b.Path)
+}
+
+// BackupDir is the directory holding the backups of filePath, next to it:
+// default.json is backed up into default.json.backups.
+func BackupDir(filePath string) string {
+	return filePath + ".backups"
+}
+
+// backupQuotesFile copies the quotes in filePath, with their IDs and its
+// journal applied, into its backup directory and drops the oldest backups
+// beyond BackupCount. A file that does not load is copied as it is. A missing
+// filePath needs no backup.
+func backupQuotesFile(filePath string) error {
+	if BackupCount <= 0 {
+		return markUnbacked(filePath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the loaded quotes rather than the bytes, so a file without IDs is
+	// backed up with the IDs its quotes are known by
+	if quoteList, err := loadCollection(filePath); err == nil {
+		data, err = json.MarshalIndent(quoteList, "", "\t")
+		if err != nil {
+			return err
+		}
+	}
+
+	return saveBackup(filePath, data)
+}
+
+// saveBackup stores data as the newest backup of filePath and drops the
+// oldest backups beyond BackupCount.
+func saveBackup(filePath string, data []byte) error {
+	if BackupCount <= 0 {
+		return markUnbacked(filePath)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	dir := BackupDir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// O_EXCL, so two backups in the same nanosecond cannot overwrite each
+	// other; the later one moves on to the next free name
+	now := time.Now().UTC()
+	for {
+		backupPath := filepath.Join(dir, now.Format(backupTimeFormat)+".json")
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+		if errors.Is(err, fs.ErrExist) {
+			now = now.Add(time.Nanosecond)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(backupPath)
+			return err
+		}
+		break
+	}
+
+	return pruneBackups(filePath, BackupCount)
+}
+
+// unbackedName is the file in a backup directory recording when its quotes
+// file was last changed without a backup, see markUnbacked.
+const unbackedName = "unbacked"
+
+// markUnbacked records that filePath is being changed without a backup (with
+// BackupCount zero), so Undo knows its newest backup is not from the last
+// change. A file without backups has nothing to mark.
+func markUnbacked(filePath string) error {
+	dir := BackupDir(filePath)
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+	now := time.Now().UTC().Format(backupTimeFormat)
+	return writeFileAtomic(filepath.Join(dir, unbackedName), []byte(now+"\n"), 0644)
+}
+
+// unbackedSince returns when filePath was last changed without a backup, or
+// the zero time if it never was.
+func unbackedSince(filePath string) time.Time {
+	data, err := os.ReadFile(filepath.Join(BackupDir(filePath), unbackedName))
+	if err != nil {
+		return time.Time{}
+	}
+	changed, err := time.Parse(backupTimeFormat, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}
+	}
+	return changed
+}
+
+// pruneBackups deletes all but the newest keep backups of filePath.
+func pruneBackups(filePath string, keep int) error {
+	backups, err := ListBackups(filePath)
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(keep, len(backups)):] {
+		if err := os.Remove(backup.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBackups returns the backups of filePath, newest first. A file that was
+// never backed up has none.
+func ListBackups(filePath string) ([]Backup, error) {
+	entries, err := os.ReadDir(BackupDir(filePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups of %q: %w", filePath, err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		name, found := strings.CutSuffix(entry.Name(), ".json")
+		if !found || entry.IsDir() {
+			continue
+		}
+		taken, err := time.Parse(backupTimeFormat, name)
+		if err != nil {
+			continue // not ours
+		}
+		backups = append(backups, Backup{Path: filepath.Join(BackupDir(filePath), entry.Name()), Time: taken})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces filePath with the backup called name (see
+// Backup.Name), failing with ErrNoBackup if there is none. The current file is
+// backed up first, so a restore can itself be undone. The backup must hold a
+// valid quote list.
+func RestoreBackup(name string, filePath string) (Backup, error) {
+	unlock, err := lockQuotesFile(filePath)
+	if err != nil {
+		return Backup{}, err
+	}
+	defer unlock()
+
+	backup, err := findBackup(name, filePath)
+	if err != nil {
+		return Backup{}, err
+	}
+	if err := recordIDsInUse(filePath); err != nil {
+		return Backup{}, &WriteError{Path: filePath, Err: err}
+	}
+	return backup, restoreBackup(backup, filePath, true)
+}
+
+// Undo puts filePath back the way it was before the last change and returns
+// when that change was made. The last change in the journal (see UseJournal)
+// is dropped if there is one, otherwise the newest backup is restored and
+// used up, so undoing again goes one more change back. It fails with
+// ErrNoBackup when there is nothing left to undo, or when the file was changed
+// without a backup (see BackupCount) after the newest backup was taken, since
+// restoring that would take back more than the last change; RestoreBackup
+// still can.
+func Undo(filePath string) (time.Time, error) {
+	unlock, err := lockQuotesFile(filePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer unlock()
+
+	if err := recordIDsInUse(filePath); err != nil {
+		return time.Time{}, &WriteError{Path: filePath, Err: err}
+	}
+	snapshot, err := os.ReadFile(filePath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read quotes file %q: %w", filePath, err)
+	}
+	j, err := readJournal(filePath, snapshot)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(j.entries) > 0 {
+		dropped, err := j.dropLastChange()
+		if err != nil {
+			return time.Time{}, &WriteError{Path: j.path, Err: err}
+		}
+		return dropped[0].Time, nil
+	}
+
+	backups, err := ListBackups(filePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(backups) == 0 {
+		return time.Time{}, fmt.Errorf("%w to undo for %q", ErrNoBackup, filePath)
+	}
+
+	newest := backups[0]
+	if unbacked := unbackedSince(filePath); unbacked.After(newest.Time) {
+		return time.Time{}, fmt.Errorf("%w to undo for %q: it was changed without a backup at %s",
+			ErrNoBackup, filePath, unbacked.Local().Format(time.DateTime))
+	}
+	if err := restoreBackup(newest, filePath, false); err != nil {
+		return time.Time{}, err
+	}
+	if err := os.Remove(newest.Path); err != nil {
+		return time.Time{}, err
+	}
+	return newest.Time, nil
+}
+
+// findBackup returns the backup of filePath called name.
+func findBackup(name string, filePath string) (Backup, error) {
+	backups, err := ListBackups(filePath)
+	if err != nil {
+		return Backup{}, err
+	}
+	for _, backup := range backups {
+		if backup.Name() == name {
+			return backup, nil
+		}
+	}
+	return Backup{}, fmt.Errorf("%w called %q for %q", ErrNoBackup, name, filePath)
+}
+
+// restoreBackup copies backup over filePath, first backing up the current
+// file when isBackedUp is set. The caller holds the lock.
+func restoreBackup(backup Backup, filePath string, isBackedUp bool) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup %q: %w", backup.Path, err)
+	}
+
+	// refuse to restore something the loader would reject
+	if _, err := loadCollection(backup.Path); err != nil {
+		return err
+	}
+
+	if isBackedUp {
+		if err := backupQuotesFile(filePath); err != nil {
+			return &WriteError{Path: filePath, Err: err}
+		}
+	}
+	if err := writeFileAtomic(filePath, data, 0644); err != nil {
+		return &WriteError{Path: filePath, Err: err}
+	}
+	archiveJournal(filePath)
+	return nil
+}
@@ -0,0 +1,197 @@
+package quotes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// quoteTexts loads filePath and returns the text of every quote in it.
+func quoteTexts(t *testing.T, filePath string) []string {
+	t.Helper()
+	quoteList, err := LoadQuotesFromFile(filePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	texts := make([]string, len(quoteList))
+	for i, quote := range quoteList {
+		texts[i] = quote.Text
+	}
+	return texts
+}
+
+// TestBackups_Rotation tests that every write keeps a backup of the previous
+// file, up to BackupCount of them.
+func TestBackups_Rotation(t *testing.T) {
+	defer func(old int) { BackupCount = old }(BackupCount)
+	BackupCount = 3
+
+	testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+	if backups, _ := ListBackups(testFilePath); len(backups) != 0 {
+		t.Errorf("creating a file made %d backups; want 0", len(backups))
+	}
+
+	for _, text := range []string{"v2", "v3", "v4", "v5"} {
+		if err := WriteQuoteToFile([]Quote{{Text: text}}, testFilePath); err != nil {
+			t.Fatalf("WriteQuoteToFile() returned an unexpected error: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(testFilePath)
+	if err != nil {
+		t.Fatalf("ListBackups() returned an unexpected error: %v", err)
+	}
+	var got []string
+	for _, backup := range backups {
+		got = append(got, quoteTexts(t, backup.Path)...)
+	}
+	if expected := []string{"v4", "v3", "v2"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("backups hold %v; want %v (newest first)", got, expected)
+	}
+}
+
+// TestBackups_Disabled tests that a BackupCount of zero writes no backups.
+func TestBackups_Disabled(t *testing.T) {
+	defer func(old int) { BackupCount = old }(BackupCount)
+	BackupCount = 0
+
+	testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+	if err := WriteQuoteToFile([]Quote{{Text: "v2"}}, testFilePath); err != nil {
+		t.Fatalf("WriteQuoteToFile() returned an unexpected error: %v", err)
+	}
+	if _, err := os.Stat(BackupDir(testFilePath)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("backup directory exists with backups disabled: %v", err)
+	}
+}
+
+// TestUndo tests stepping back through changes until no backups are left.
+func TestUndo(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+	if err := AddNewQuote("New", "Someone", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	if _, err := RemoveQuote(1, testFilePath); err != nil {
+		t.Fatalf("RemoveQuote() returned an unexpected error: %v", err)
+	}
+
+	steps := [][]string{
+		{"Great work", "Be yourself", "To be", "New"}, // removal undone
+		{"Great work", "Be yourself", "To be"},        // add undone
+	}
+	for _, expected := range steps {
+		if _, err := Undo(testFilePath); err != nil {
+			t.Fatalf("Undo() returned an unexpected error: %v", err)
+		}
+		if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+			t.Errorf("after Undo() file holds %v; want %v", got, expected)
+		}
+	}
+
+	if _, err := Undo(testFilePath); !errors.Is(err, ErrNoBackup) {
+		t.Errorf("Undo() with no backups left error = %v; want ErrNoBackup", err)
+	}
+}
+
+// TestUndo_AfterUnbackedChanges tests that undo refuses to restore a backup
+// older than changes made with backups turned off, which would lose them.
+func TestUndo_AfterUnbackedChanges(t *testing.T) {
+	defer func(old int) { BackupCount = old }(BackupCount)
+
+	testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+	if err := AddNewQuote("v2", "", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	BackupCount = 0
+	for _, text := range []string{"v3", "v4"} {
+		if err := AddNewQuote(text, "", nil, testFilePath); err != nil {
+			t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+		}
+	}
+	BackupCount = 10
+
+	if _, err := Undo(testFilePath); !errors.Is(err, ErrNoBackup) {
+		t.Errorf("Undo() after unbacked changes error = %v; want ErrNoBackup", err)
+	}
+	expected := []string{"v1", "v2", "v3", "v4"}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+		t.Errorf("after refused Undo() file holds %v; want %v", got, expected)
+	}
+
+	// a change backed up again can be undone, but no further
+	if err := AddNewQuote("v5", "", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	if _, err := Undo(testFilePath); err != nil {
+		t.Fatalf("Undo() returned an unexpected error: %v", err)
+	}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+		t.Errorf("after Undo() file holds %v; want %v", got, expected)
+	}
+	if _, err := Undo(testFilePath); !errors.Is(err, ErrNoBackup) {
+		t.Errorf("second Undo() error = %v; want ErrNoBackup", err)
+	}
+}
+
+// TestUndo_LegacyFile tests that undoing the first change to a file without
+// IDs keeps the IDs its quotes were shown with.
+func TestUndo_LegacyFile(t *testing.T) {
+	testFilePath := filepath.Join(t.TempDir(), "quotes.json")
+	if err := os.WriteFile(testFilePath, []byte(`[{"text": "one"}, {"text": "two"}]`), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := AddNewQuote("three", "", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	if _, err := Undo(testFilePath); err != nil {
+		t.Fatalf("Undo() returned an unexpected error: %v", err)
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	if ids := quoteIDs(quoteList); !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("IDs after undoing the first change = %v; want [1 2]", ids)
+	}
+}
+
+// TestRestoreBackup tests restoring a named backup, and undoing the restore.
+func TestRestoreBackup(t *testing.T) {
+	testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+	for _, text := range []string{"v2", "v3"} {
+		if err := WriteQuoteToFile([]Quote{{Text: text}}, testFilePath); err != nil {
+			t.Fatalf("WriteQuoteToFile() returned an unexpected error: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(testFilePath)
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("ListBackups() = %v, %v; want 2 backups", backups, err)
+	}
+	oldest := backups[len(backups)-1]
+
+	restored, err := RestoreBackup(oldest.Name(), testFilePath)
+	if err != nil {
+		t.Fatalf("RestoreBackup() returned an unexpected error: %v", err)
+	}
+	if restored != oldest {
+		t.Errorf("RestoreBackup() = %v; want %v", restored, oldest)
+	}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, []string{"v1"}) {
+		t.Errorf("after RestoreBackup() file holds %v; want [v1]", got)
+	}
+
+	// the restore backed up v3 first
+	if _, err := Undo(testFilePath); err != nil {
+		t.Fatalf("Undo() returned an unexpected error: %v", err)
+	}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, []string{"v3"}) {
+		t.Errorf("after undoing the restore file holds %v; want [v3]", got)
+	}
+
+	if _, err := RestoreBackup("missing.json", testFilePath); !errors.Is(err, ErrNoBackup) {
+		t.Errorf("RestoreBackup() of a missing backup error = %v; want ErrNoBackup", err)
+	}
+}
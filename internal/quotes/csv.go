@@ -0,0 +1,163 @@
+package quotes
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ====================================================== \\
+//	CSV
+// ====================================================== \\
+
+// csvColumns are the columns WriteCSV writes, in order, and the header names
+// ReadCSV recognises.
+var csvColumns = []string{"id", "text", "author", "tags"}
+
+// csvColumnAliases are other header names spreadsheets use for our columns.
+var csvColumnAliases = map[string]string{
+	"quote": "text",
+	"tag":   "tags",
+}
+
+// csvTagSeparator joins the tags of a quote into one cell. ReadCSV also
+// splits tags on commas.
+const csvTagSeparator = "; "
+
+// ReadCSV reads quotes from CSV (RFC 4180, so text with delimiters, quotes or
+// newlines is quoted) whose fields are separated by delimiter. name labels
+// the input in errors, e.g. its path.
+//
+// A first row naming the columns (id, text or quote, author, tags, in any
+// order and case) is a header; any other column is ignored. Without a header
+// the columns are text, author and tags. Tags are one cell separated by
+// semicolons or commas, and an empty ID is left for AssignIDs. Blank rows are
+// skipped. Errors are a *ParseError pointing at the problem.
+func ReadCSV(r io.Reader, delimiter rune, name string) ([]Quote, error) {
+	reader := csv.NewReader(skipBOM(r))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1 // spreadsheets drop trailing empty cells
+
+	columns := map[string]int{"text": 0, "author": 1, "tags": 2}
+	var quoteList []Quote
+	for isFirst := true; ; isFirst = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return quoteList, nil
+		}
+		if err != nil {
+			return nil, newCSVParseError(name, err)
+		}
+
+		if isFirst {
+			if header, ok := csvHeader(record); ok {
+				columns = header
+				continue
+			}
+		}
+
+		cell := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		quote := Quote{Text: cell("text"), Author: cell("author"), Tags: splitTags(cell("tags"))}
+		if quote.Text == "" {
+			line, _ := reader.FieldPos(0)
+			return nil, &ParseError{Path: name, Line: line, Column: 1, Err: errors.New("row has no quote text")}
+		}
+		if id := cell("id"); id != "" {
+			quote.ID, err = strconv.Atoi(id)
+			if err != nil || quote.ID < 0 {
+				line, column := reader.FieldPos(columns["id"])
+				return nil, &ParseError{Path: name, Line: line, Column: column, Err: fmt.Errorf("invalid id %q", id)}
+			}
+		}
+		quoteList = append(quoteList, quote)
+	}
+}
+
+// WriteCSV writes quoteList as CSV with a header row, separating fields with
+// delimiter. ReadCSV reads the result back.
+func WriteCSV(w io.Writer, quoteList []Quote, delimiter rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, quote := range quoteList {
+		record := []string{strconv.Itoa(quote.ID), quote.Text, quote.Author, strings.Join(quote.Tags, csvTagSeparator)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvHeader returns the column positions named by record if it is a header:
+// it names the text column and, unless it is the only cell, another known
+// column too.
+func csvHeader(record []string) (map[string]int, bool) {
+	columns := make(map[string]int)
+	cells := 0
+	for i, cell := range record {
+		cell = strings.ToLower(strings.TrimSpace(cell))
+		if alias, ok := csvColumnAliases[cell]; ok {
+			cell = alias
+		}
+		if cell == "" {
+			continue
+		}
+		cells++
+		if _, seen := columns[cell]; !seen && slices.Contains(csvColumns, cell) {
+			columns[cell] = i
+		}
+	}
+
+	_, hasText := columns["text"]
+	return columns, hasText && (len(columns) >= 2 || cells == 1)
+}
+
+// splitTags splits a tags cell on semicolons and commas, dropping blanks.
+func splitTags(cell string) []string {
+	tags := []string{}
+	for _, tag := range strings.FieldsFunc(cell, func(r rune) bool { return r == ';' || r == ',' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// skipBOM drops the byte order mark spreadsheets put at the start of UTF-8
+// CSV files.
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+	return buffered
+}
+
+// newCSVParseError builds the ParseError for an error from csv.Reader.
+func newCSVParseError(name string, err error) *ParseError {
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		return &ParseError{Path: name, Line: csvErr.Line, Column: csvErr.Column, Err: csvErr.Err}
+	}
+	return &ParseError{Path: name, Err: err}
+}
@@ -0,0 +1,112 @@
+package quotes
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadCSV tests reading quotes with and without a header row.
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		delimiter rune
+		expected  []Quote
+	}{
+		{
+			name:      "Header in any order",
+			content:   "Author,Quote,Tags\nSteve Jobs,Great work,work; life\n",
+			delimiter: ',',
+			expected:  []Quote{{Text: "Great work", Author: "Steve Jobs", Tags: []string{"work", "life"}}},
+		},
+		{
+			name:      "No header",
+			content:   "Great work,Steve Jobs,\"work,life\"\nBe yourself,Oscar Wilde\n",
+			delimiter: ',',
+			expected: []Quote{
+				{Text: "Great work", Author: "Steve Jobs", Tags: []string{"work", "life"}},
+				{Text: "Be yourself", Author: "Oscar Wilde", Tags: []string{}},
+			},
+		},
+		{
+			name:      "Quoted commas, quotes and newlines",
+			content:   "id,text,author,tags\n7,\"One, two\nthree \"\"four\"\"\",Someone,\n",
+			delimiter: ',',
+			expected:  []Quote{{ID: 7, Text: "One, two\nthree \"four\"", Author: "Someone", Tags: []string{}}},
+		},
+		{
+			name:      "Semicolon delimiter, BOM and blank rows",
+			content:   "\ufefftext;author\nTo be;William Shakespeare\n;\n",
+			delimiter: ';',
+			expected:  []Quote{{Text: "To be", Author: "William Shakespeare", Tags: []string{}}},
+		},
+		{
+			name:      "Unknown columns ignored",
+			content:   "text\tsource\tauthor\nTo be\tHamlet\tWilliam Shakespeare\n",
+			delimiter: '\t',
+			expected:  []Quote{{Text: "To be", Author: "William Shakespeare", Tags: []string{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV(strings.NewReader(tt.content), tt.delimiter, "test.csv")
+			if err != nil {
+				t.Fatalf("ReadCSV() returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ReadCSV() = %#v; want %#v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestReadCSV_Errors tests that bad rows are a *ParseError with their line.
+func TestReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectedLine int
+	}{
+		{name: "Unterminated quote", content: "text,author\n\"Great work,Steve Jobs\n", expectedLine: 2},
+		{name: "Missing text", content: "text,author\nTo be,William Shakespeare\n,Steve Jobs\n", expectedLine: 3},
+		{name: "Bad id", content: "id,text\nseven,To be\n", expectedLine: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tt.content), ',', "test.csv")
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ReadCSV() error = %v; want *ParseError", err)
+			}
+			if parseErr.Path != "test.csv" || parseErr.Line != tt.expectedLine {
+				t.Errorf("ParseError at %q line %d; want %q line %d", parseErr.Path, parseErr.Line, "test.csv", tt.expectedLine)
+			}
+		})
+	}
+}
+
+// TestWriteCSV tests that written CSV reads back the same.
+func TestWriteCSV(t *testing.T) {
+	quoteList := testQuotes()
+	quoteList[0].Text = "Great, \"great\"\nwork"
+	quoteList[1].Tags = []string{"humor", "life"}
+
+	for _, delimiter := range []rune{',', ';', '\t'} {
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, quoteList, delimiter); err != nil {
+			t.Fatalf("WriteCSV() returned an unexpected error: %v", err)
+		}
+		got, err := ReadCSV(&buf, delimiter, "test.csv")
+		if err != nil {
+			t.Fatalf("ReadCSV() returned an unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, quoteList) {
+			t.Errorf("round trip with %q = %v; want %v", delimiter, got, quoteList)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package quotes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ====================================================== \\
+//	Errors
+// ====================================================== \\
+
+// Sentinel errors for the failures callers are expected to handle. Check for
+// them with errors.Is; the returned errors carry the details (which file,
+// which id).
+var (
+	// ErrNotFound is matched by *NotFoundError.
+	ErrNotFound = errors.New("quote not found")
+	// ErrEmptyCollection is wrapped when reading a quotes file that holds no
+	// quotes. Changes start from the empty collection instead.
+	ErrEmptyCollection = errors.New("no quotes found")
+	// ErrLocked is matched by *LockedError.
+	ErrLocked = errors.New("collection is locked")
+	// ErrNoBackup is wrapped when a backup to restore does not exist.
+	ErrNoBackup = errors.New("no backup")
+)
+
+// NotFoundError is returned when no quote in a file has the requested ID.
+type NotFoundError struct {
+	ID   int
+	Path string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no quote with id %d in %q", e.ID, e.Path)
+}
+
+// Is makes errors.Is(err, ErrNotFound) true.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ParseError is returned when a quotes file is not valid JSON or does not
+// hold a list of quotes, or when a file being imported cannot be read. Line
+// and Column (both 1-based) point at the problem, or are 0 when the decoder
+// gave no position.
+type ParseError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error // e.g. the *json.SyntaxError or *json.UnmarshalTypeError
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("failed to parse %q: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("failed to parse %q at line %d, column %d: %v", e.Path, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError builds the ParseError for a json.Unmarshal error on data.
+func newParseError(path string, data []byte, err error) *ParseError {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return &ParseError{Path: path, Err: err}
+	}
+
+	line, column := position(data, offset)
+	return &ParseError{Path: path, Line: line, Column: column, Err: err}
+}
+
+// position converts a byte offset into data to a 1-based line and column
+// (counted in bytes). The JSON decoder reports the offset just past the
+// problem, so the column points at its last byte.
+func position(data []byte, offset int64) (int, int) {
+	offset = min(max(offset, 1), int64(len(data)))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n') - 1
+	return line, max(column, 1)
+}
+
+// WriteError is returned when a quotes file cannot be written. The file on
+// disk is unchanged (see writeFileAtomic).
+type WriteError struct {
+	Path string
+	Err  error
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("failed to write %q: %v", e.Path, e.Err)
+}
+
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
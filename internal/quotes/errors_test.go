@@ -0,0 +1,87 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadQuotesFromFile_ParseError tests that malformed files report the
+// line and column of the problem.
+func TestLoadQuotesFromFile_ParseError(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		expectedLine   int
+		expectedColumn int
+		expectedErr    any
+	}{
+		{name: "Missing comma", content: "[\n  {\"text\": \"a\"}\n  {\"text\": \"b\"}\n]", expectedLine: 3, expectedColumn: 3, expectedErr: &json.SyntaxError{}},
+		{name: "Trailing comma", content: "[\n\t{\"text\": \"a\"},\n]", expectedLine: 3, expectedColumn: 1, expectedErr: &json.SyntaxError{}},
+		{name: "Wrong type", content: "[\n  {\"text\": \"a\", \"tags\": \"work\"}\n]", expectedLine: 2, expectedColumn: 30, expectedErr: &json.UnmarshalTypeError{}},
+		{name: "Empty file", content: "", expectedLine: 1, expectedColumn: 1, expectedErr: &json.SyntaxError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFilePath := filepath.Join(t.TempDir(), "quotes.json")
+			if err := os.WriteFile(testFilePath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			_, err := LoadQuotesFromFile(testFilePath)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("LoadQuotesFromFile() error = %v; want *ParseError", err)
+			}
+			if parseErr.Path != testFilePath || parseErr.Line != tt.expectedLine || parseErr.Column != tt.expectedColumn {
+				t.Errorf("ParseError at %q %d:%d; want %q %d:%d", parseErr.Path, parseErr.Line, parseErr.Column,
+					testFilePath, tt.expectedLine, tt.expectedColumn)
+			}
+			if reflect.TypeOf(parseErr.Err) != reflect.TypeOf(tt.expectedErr) {
+				t.Errorf("ParseError wraps a %T; want %T", parseErr.Err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+// TestSentinelErrors tests that failures can be told apart with errors.Is and
+// errors.As.
+func TestSentinelErrors(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	_, err := RemoveQuote(42, testFilePath)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveQuote() on a missing id error = %v; want ErrNotFound", err)
+	}
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.ID != 42 {
+		t.Errorf("RemoveQuote() error = %v; want *NotFoundError for id 42", err)
+	}
+	if err := UpdateQuote(Quote{ID: 42}, testFilePath); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateQuote() on a missing id error = %v; want ErrNotFound", err)
+	}
+
+	emptyFilePath := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(emptyFilePath, []byte("[]"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if _, err := LoadQuotesFromFile(emptyFilePath); !errors.Is(err, ErrEmptyCollection) {
+		t.Errorf("LoadQuotesFromFile() on an empty file error = %v; want ErrEmptyCollection", err)
+	}
+
+	// a directory cannot be replaced by a file
+	dirPath := t.TempDir()
+	err = WriteQuoteToFile(testQuotes(), dirPath)
+	var writeErr *WriteError
+	if !errors.As(err, &writeErr) || writeErr.Path != dirPath {
+		t.Errorf("WriteQuoteToFile() over a directory error = %v; want *WriteError", err)
+	}
+
+	if !errors.Is(&LockedError{Path: testFilePath, PID: 1}, ErrLocked) {
+		t.Error("LockedError does not match ErrLocked")
+	}
+}
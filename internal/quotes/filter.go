@@ -0,0 +1,129 @@
+package quotes
+
+import (
+	"strings"
+)
+
+// ====================================================== \\
+//	Search Filters
+// ====================================================== \\
+
+// Filter decides whether a quote belongs in a search result. Filters are
+// combined with AndFilter, OrFilter and NotFilter to build a query, e.g. a
+// tag AND an author AND a keyword:
+//
+//	Search(quoteList, AndFilter{
+//		TagFilter{Tag: "work"},
+//		AuthorFilter{Author: "jobs"},
+//		TextFilter{Text: "great"},
+//	})
+type Filter interface {
+	Match(quote Quote) bool
+}
+
+// TagFilter matches quotes that have a tag equal to (Exact), containing, or
+// when Fuzzy within a few typos of Tag. Matching is case and accent
+// insensitive (see normalize) and ignores leading/trailing whitespace on Tag;
+// an empty Tag matches nothing.
+type TagFilter struct {
+	Tag   string
+	Exact bool
+	Fuzzy bool
+}
+
+func (f TagFilter) Match(quote Quote) bool {
+	targetTag := normalize(strings.TrimSpace(f.Tag))
+	if targetTag == "" {
+		return false
+	}
+
+	for _, quoteTag := range quote.Tags {
+		if matchField(normalize(quoteTag), targetTag, f.Exact, f.Fuzzy) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthorFilter matches quotes whose author equals (Exact), contains, or when
+// Fuzzy is within a few typos of Author ("Shakespear" finds "William
+// Shakespeare"). Matching is case and accent insensitive (see normalize) and
+// ignores leading/trailing whitespace on Author; an empty Author matches
+// nothing.
+type AuthorFilter struct {
+	Author string
+	Exact  bool
+	Fuzzy  bool
+}
+
+func (f AuthorFilter) Match(quote Quote) bool {
+	authorName := normalize(strings.TrimSpace(f.Author))
+	if authorName == "" {
+		return false
+	}
+
+	return matchField(normalize(quote.Author), authorName, f.Exact, f.Fuzzy)
+}
+
+// TextFilter matches quotes whose text contains the words of Text, see
+// SearchByText. Mode picks whether all (the default), any, or all of the words
+// as a phrase must match; an empty Text matches nothing.
+type TextFilter struct {
+	Text  string
+	Exact bool
+	Mode  TermMode
+}
+
+func (f TextFilter) Match(quote Quote) bool {
+	return matchTerms(tokenize(quote.Text), tokenize(f.Text), f.Exact, f.Mode)
+}
+
+// AndFilter matches quotes that match every one of its filters. An empty
+// AndFilter matches everything.
+type AndFilter []Filter
+
+func (f AndFilter) Match(quote Quote) bool {
+	for _, filter := range f {
+		if !filter.Match(quote) {
+			return false
+		}
+	}
+	return true
+}
+
+// OrFilter matches quotes that match at least one of its filters. An empty
+// OrFilter matches nothing.
+type OrFilter []Filter
+
+func (f OrFilter) Match(quote Quote) bool {
+	for _, filter := range f {
+		if filter.Match(quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotFilter matches quotes that Filter does not.
+type NotFilter struct {
+	Filter Filter
+}
+
+func (f NotFilter) Match(quote Quote) bool {
+	return !f.Filter.Match(quote)
+}
+
+// Search returns the quotes that match filter, in their original order.
+// If nothing matches the result is nil.
+func Search(quotes []Quote, filter Filter) []Quote {
+	var matchingQuotes []Quote
+
+	for _, quote := range quotes {
+		if filter.Match(quote) {
+			matchingQuotes = append(matchingQuotes, quote)
+		}
+	}
+
+	return matchingQuotes
+}
@@ -0,0 +1,85 @@
+package quotes
+
+import (
+	"reflect"
+	"testing"
+)
+
+//				Test - Search with Filters
+// ====================================================== \\
+
+// TestSearch_Filters tests that combined filters intersect (or union) correctly.
+func TestSearch_Filters(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "The only way to do great work is to love what you do.", Author: "Steve Jobs", Tags: []string{"inspiration", "work"}},
+		{ID: 2, Text: "Innovation distinguishes between a leader and a follower.", Author: "Steve Jobs", Tags: []string{"innovation", "work"}},
+		{ID: 3, Text: "Great things are done by a series of small things.", Author: "Vincent van Gogh", Tags: []string{"work", "art"}},
+		{ID: 4, Text: "Be yourself; everyone else is already taken.", Author: "Oscar Wilde", Tags: []string{"identity"}},
+	}
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		expectedIDs []int
+	}{
+		{
+			name:        "Tag AND author",
+			filter:      AndFilter{TagFilter{Tag: "work"}, AuthorFilter{Author: "jobs"}},
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name: "Tag AND author AND keyword",
+			filter: AndFilter{
+				TagFilter{Tag: "work"},
+				AuthorFilter{Author: "jobs"},
+				TextFilter{Text: "great"},
+			},
+			expectedIDs: []int{1},
+		},
+		{
+			name:        "Several required tags",
+			filter:      AndFilter{TagFilter{Tag: "work", Exact: true}, TagFilter{Tag: "art", Exact: true}},
+			expectedIDs: []int{3},
+		},
+		{
+			name:        "Keyword is case-insensitive",
+			filter:      TextFilter{Text: "GREAT"},
+			expectedIDs: []int{1, 3},
+		},
+		{
+			name:        "Either author",
+			filter:      OrFilter{AuthorFilter{Author: "wilde"}, AuthorFilter{Author: "gogh"}},
+			expectedIDs: []int{3, 4},
+		},
+		{
+			name:        "Tag AND NOT author",
+			filter:      AndFilter{TagFilter{Tag: "work"}, NotFilter{AuthorFilter{Author: "jobs"}}},
+			expectedIDs: []int{3},
+		},
+		{
+			name:        "Empty AND matches everything",
+			filter:      AndFilter{},
+			expectedIDs: []int{1, 2, 3, 4},
+		},
+		{
+			name:        "No match",
+			filter:      AndFilter{TagFilter{Tag: "identity"}, AuthorFilter{Author: "jobs"}},
+			expectedIDs: nil,
+		},
+		{
+			name:        "Empty tag matches nothing",
+			filter:      TagFilter{Tag: "  "},
+			expectedIDs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualIDs := quoteIDs(Search(sampleQuotes, tt.filter))
+
+			if !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("Search() \ngot  = %v, \nwant = %v", actualIDs, tt.expectedIDs)
+			}
+		})
+	}
+}
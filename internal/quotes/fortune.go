@@ -0,0 +1,261 @@
+package quotes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ====================================================== \\
+//	fortune(6) Files
+// ====================================================== \\
+
+// fortuneDelimiter is the line separating the entries of a fortune file.
+const fortuneDelimiter = "%"
+
+// fortuneAttribution starts the line naming who said an entry, e.g.
+// "\t\t-- Mark Twain".
+var fortuneAttribution = []string{"--", "—", "―"}
+
+// ReadFortune reads the entries of a fortune file as quotes. A last line
+// starting with "--" (optionally indented, and followed by indented
+// continuation lines) is the author; the rest is the text, line breaks kept.
+// Entries of an offensive fortune file are stored rot13 encoded, which its
+// .dat index says with FortuneRotated; isRotated decodes them.
+func ReadFortune(r io.Reader, isRotated bool) ([]Quote, error) {
+	var quoteList []Quote
+	var lines []string
+	flush := func() {
+		if quote, ok := parseFortuneEntry(lines); ok {
+			quoteList = append(quoteList, quote)
+		}
+		lines = lines[:0]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if isRotated {
+			line = rot13(line)
+		}
+		if line == fortuneDelimiter {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return quoteList, nil
+}
+
+// parseFortuneEntry splits the lines of one fortune entry into its text and
+// attribution. Blank entries are skipped.
+func parseFortuneEntry(lines []string) (Quote, bool) {
+	lines = trimBlankLines(lines)
+	if len(lines) == 0 {
+		return Quote{}, false
+	}
+
+	// the attribution is the last "--" line, if every line after it is an
+	// indented continuation
+	for i := len(lines) - 1; i > 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if author, ok := cutAttribution(trimmed); ok {
+			for _, continuation := range lines[i+1:] {
+				author += " " + strings.TrimSpace(continuation)
+			}
+			text := strings.Join(trimBlankLines(lines[:i]), "\n")
+			return Quote{Text: text, Author: strings.TrimSpace(author), Tags: []string{}}, true
+		}
+		if lines[i] == trimmed {
+			break // not indented, so not part of an attribution
+		}
+	}
+
+	return Quote{Text: strings.Join(lines, "\n"), Tags: []string{}}, true
+}
+
+// cutAttribution returns line without its leading attribution dash.
+func cutAttribution(line string) (string, bool) {
+	for _, dash := range fortuneAttribution {
+		if author, found := strings.CutPrefix(line, dash); found {
+			return author, strings.TrimSpace(author) != ""
+		}
+	}
+	return "", false
+}
+
+// trimBlankLines drops the blank lines at both ends of lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// WriteFortune writes quoteList as a fortune file, each entry followed by a
+// "%" line and its author on an indented "-- " line. Pair it with
+// BuildFortuneIndex for the .dat file fortune reads.
+func WriteFortune(w io.Writer, quoteList []Quote) error {
+	bw := bufio.NewWriter(w)
+	for _, quote := range quoteList {
+		for _, line := range strings.Split(strings.TrimSpace(quote.Text), "\n") {
+			// a line of just "%" would end the entry early
+			if strings.TrimSpace(line) == fortuneDelimiter {
+				line = " " + line
+			}
+			fmt.Fprintln(bw, line)
+		}
+		if author := strings.TrimSpace(quote.Author); author != "" {
+			fmt.Fprintf(bw, "\t\t-- %s\n", author)
+		}
+		fmt.Fprintln(bw, fortuneDelimiter)
+	}
+	return bw.Flush()
+}
+
+// rot13 rotates the ASCII letters of s by 13 places, which undoes itself.
+func rot13(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, s)
+}
+
+// ====================================================== \\
+//	strfile(8) Index
+// ====================================================== \\
+
+// Flags of a FortuneIndex.
+const (
+	FortuneRandom  uint32 = 0x1 // entries were shuffled
+	FortuneOrdered uint32 = 0x2 // entries were sorted
+	FortuneRotated uint32 = 0x4 // entries are rot13 encoded
+)
+
+// fortuneIndexVersion is the strfile format version we write.
+const fortuneIndexVersion = 2
+
+// FortuneIndex is the .dat file strfile(8) builds next to a fortune file so
+// fortune can seek straight to a random entry. It is stored big-endian: a
+// header of five uint32s and the delimiter padded to four bytes, then one
+// uint32 offset per entry plus the end of the last, the layout of
+// fortune-mod's strfile.
+type FortuneIndex struct {
+	Version   uint32
+	Longest   uint32 // length of the longest entry
+	Shortest  uint32 // length of the shortest entry
+	Flags     uint32
+	Delimiter byte
+	Offsets   []uint32 // where each entry starts, then where the last ends
+}
+
+// Count is how many entries the index holds.
+func (idx FortuneIndex) Count() int {
+	return max(len(idx.Offsets)-1, 0)
+}
+
+// BuildFortuneIndex indexes fortune, the contents of a fortune file, the way
+// strfile does: every entry starts right after a delimiter line, and empty
+// entries are left out.
+func BuildFortuneIndex(fortune []byte) FortuneIndex {
+	idx := FortuneIndex{Version: fortuneIndexVersion, Delimiter: fortuneDelimiter[0], Offsets: []uint32{0}}
+
+	start := 0
+	addEntry := func(end int, next int) {
+		length := uint32(end - start)
+		start = next
+		if length == 0 {
+			idx.Offsets[len(idx.Offsets)-1] = uint32(next)
+			return
+		}
+		idx.Offsets = append(idx.Offsets, uint32(next))
+		idx.Longest = max(idx.Longest, length)
+		if idx.Shortest == 0 || length < idx.Shortest {
+			idx.Shortest = length
+		}
+	}
+
+	for pos := 0; pos < len(fortune); {
+		end := bytes.IndexByte(fortune[pos:], '\n')
+		if end < 0 {
+			break
+		}
+		next := pos + end + 1
+		if string(fortune[pos:pos+end]) == fortuneDelimiter {
+			addEntry(pos, next)
+		}
+		pos = next
+	}
+	addEntry(len(fortune), len(fortune))
+
+	return idx
+}
+
+// fortuneIndexHeader is the fixed part of a .dat file.
+type fortuneIndexHeader struct {
+	Version  uint32
+	Count    uint32
+	Longest  uint32
+	Shortest uint32
+	Flags    uint32
+	Stuff    [4]byte // the delimiter, then padding
+}
+
+// WriteTo writes the index in the .dat format.
+func (idx FortuneIndex) WriteTo(w io.Writer) (int64, error) {
+	header := fortuneIndexHeader{
+		Version:  idx.Version,
+		Count:    uint32(idx.Count()),
+		Longest:  idx.Longest,
+		Shortest: idx.Shortest,
+		Flags:    idx.Flags,
+		Stuff:    [4]byte{idx.Delimiter},
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, header)
+	binary.Write(&buf, binary.BigEndian, idx.Offsets)
+	return buf.WriteTo(w)
+}
+
+// ReadFortuneIndex reads a .dat file written by strfile or WriteTo.
+func ReadFortuneIndex(r io.Reader) (FortuneIndex, error) {
+	var header fortuneIndexHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return FortuneIndex{}, fmt.Errorf("invalid fortune index: %w", err)
+	}
+	if header.Count > 1<<24 {
+		return FortuneIndex{}, fmt.Errorf("invalid fortune index: %d entries", header.Count)
+	}
+
+	offsets := make([]uint32, header.Count+1)
+	if err := binary.Read(r, binary.BigEndian, offsets); err != nil {
+		return FortuneIndex{}, fmt.Errorf("invalid fortune index: %w", err)
+	}
+
+	return FortuneIndex{
+		Version:   header.Version,
+		Longest:   header.Longest,
+		Shortest:  header.Shortest,
+		Flags:     header.Flags,
+		Delimiter: header.Stuff[0],
+		Offsets:   offsets,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package quotes
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadFortune tests splitting fortune entries into text and author.
+func TestReadFortune(t *testing.T) {
+	content := strings.Join([]string{
+		"%",
+		"The unexamined life is not worth living.",
+		"\t\t-- Socrates",
+		"%",
+		"Roses are red,",
+		"  violets are blue.",
+		"",
+		"%",
+		"",
+		"%",
+		"Be yourself; everyone else is already taken.",
+		"\t-- Oscar Wilde,",
+		"\t   on being yourself",
+		"%",
+		"Two dashes -- in the text",
+		"-- not indented, still the author",
+	}, "\n")
+
+	got, err := ReadFortune(strings.NewReader(content), false)
+	if err != nil {
+		t.Fatalf("ReadFortune() returned an unexpected error: %v", err)
+	}
+	expected := []Quote{
+		{Text: "The unexamined life is not worth living.", Author: "Socrates", Tags: []string{}},
+		{Text: "Roses are red,\n  violets are blue.", Tags: []string{}},
+		{Text: "Be yourself; everyone else is already taken.", Author: "Oscar Wilde, on being yourself", Tags: []string{}},
+		{Text: "Two dashes -- in the text", Author: "not indented, still the author", Tags: []string{}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReadFortune() = %#v; want %#v", got, expected)
+	}
+}
+
+// TestReadFortune_Rotated tests decoding an offensive (rot13) fortune file.
+func TestReadFortune_Rotated(t *testing.T) {
+	got, err := ReadFortune(strings.NewReader("Uryyb, jbeyq!\n\t\t-- Fbzrbar\n%\n"), true)
+	if err != nil {
+		t.Fatalf("ReadFortune() returned an unexpected error: %v", err)
+	}
+	expected := []Quote{{Text: "Hello, world!", Author: "Someone", Tags: []string{}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReadFortune() = %v; want %v", got, expected)
+	}
+}
+
+// TestWriteFortune tests that a written fortune file reads back the same.
+func TestWriteFortune(t *testing.T) {
+	quoteList := []Quote{
+		{Text: "Great work", Author: "Steve Jobs", Tags: []string{}},
+		{Text: "Line one\n%\nline three", Tags: []string{}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFortune(&buf, quoteList); err != nil {
+		t.Fatalf("WriteFortune() returned an unexpected error: %v", err)
+	}
+	expectedFile := "Great work\n\t\t-- Steve Jobs\n%\nLine one\n %\nline three\n%\n"
+	if buf.String() != expectedFile {
+		t.Errorf("WriteFortune() wrote %q; want %q", buf.String(), expectedFile)
+	}
+
+	got, err := ReadFortune(&buf, false)
+	if err != nil {
+		t.Fatalf("ReadFortune() returned an unexpected error: %v", err)
+	}
+	quoteList[1].Text = "Line one\n %\nline three"
+	if !reflect.DeepEqual(got, quoteList) {
+		t.Errorf("round trip = %v; want %v", got, quoteList)
+	}
+}
+
+// TestFortuneIndex tests building, writing and reading a strfile index.
+func TestFortuneIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		fortune  string
+		expected FortuneIndex
+	}{
+		{
+			name:     "Trailing delimiter",
+			fortune:  "A\n%\nBB\n\t\t-- X\n%\n",
+			expected: FortuneIndex{Version: 2, Longest: 10, Shortest: 2, Delimiter: '%', Offsets: []uint32{0, 4, 16}},
+		},
+		{
+			name:     "No trailing delimiter",
+			fortune:  "A\n%\nBB\n",
+			expected: FortuneIndex{Version: 2, Longest: 3, Shortest: 2, Delimiter: '%', Offsets: []uint32{0, 4, 7}},
+		},
+		{
+			name:     "Empty entries skipped",
+			fortune:  "%\nA\n%\n%\nBB\n",
+			expected: FortuneIndex{Version: 2, Longest: 3, Shortest: 2, Delimiter: '%', Offsets: []uint32{2, 8, 11}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := BuildFortuneIndex([]byte(tt.fortune))
+			if !reflect.DeepEqual(idx, tt.expected) {
+				t.Fatalf("BuildFortuneIndex() = %+v; want %+v", idx, tt.expected)
+			}
+
+			var buf bytes.Buffer
+			if _, err := idx.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() returned an unexpected error: %v", err)
+			}
+			if expectedSize := 24 + 4*len(idx.Offsets); buf.Len() != expectedSize {
+				t.Errorf("WriteTo() wrote %d bytes; want %d", buf.Len(), expectedSize)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), []byte{0, 0, 0, 2, 0, 0, 0, byte(idx.Count())}) {
+				t.Errorf("WriteTo() header starts % x; want version 2 then the count, big-endian", buf.Bytes()[:8])
+			}
+
+			read, err := ReadFortuneIndex(&buf)
+			if err != nil {
+				t.Fatalf("ReadFortuneIndex() returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(read, idx) {
+				t.Errorf("ReadFortuneIndex() = %+v; want %+v", read, idx)
+			}
+		})
+	}
+}
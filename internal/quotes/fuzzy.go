@@ -0,0 +1,155 @@
+package quotes
+
+import (
+	"sort"
+	"strings"
+)
+
+// ====================================================== \\
+//	Fuzzy Matching
+// ====================================================== \\
+
+// matchField compares a normalized field value (an author or a tag) against a
+// normalized, trimmed target. isExact wins over isFuzzy; fuzzy matching also
+// accepts everything a sub-string match does.
+func matchField(value string, target string, isExact bool, isFuzzy bool) bool {
+	switch {
+	case isExact:
+		return value == target
+	case strings.Contains(value, target):
+		return true
+	case isFuzzy:
+		return fuzzyMatch(target, value)
+	}
+	return false
+}
+
+// maxEdits is how many typos a fuzzy search for a target of length runes
+// tolerates: none for very short targets, growing with the length.
+func maxEdits(length int) int {
+	switch {
+	case length < 3:
+		return 0
+	case length < 6:
+		return 1
+	case length < 12:
+		return 2
+	}
+	return 3
+}
+
+// fuzzyMatch reports whether target is within maxEdits typos of value, or of
+// a run of words in value with as many words as target. So "shakespear"
+// matches "william shakespeare" and "eleanor rosevelt" matches
+// "eleanor roosevelt".
+func fuzzyMatch(target string, value string) bool {
+	limit := maxEdits(len([]rune(target)))
+	if limit == 0 {
+		return false
+	}
+	return fuzzyDistance(target, value) <= limit
+}
+
+// fuzzyDistance returns the smallest edit distance between target and value
+// or any run of words in value with as many words as target.
+func fuzzyDistance(target string, value string) int {
+	best := editDistance(target, value)
+
+	targetWords := len(strings.Fields(target))
+	valueWords := strings.Fields(value)
+	for start := 0; start+targetWords <= len(valueWords); start++ {
+		window := strings.Join(valueWords[start:start+targetWords], " ")
+		best = min(best, editDistance(target, window))
+	}
+
+	return best
+}
+
+// editDistance is the optimal string alignment distance between a and b: the
+// number of single rune insertions, deletions, substitutions or swaps of two
+// neighbouring runes needed to turn a into b.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// three rolling rows of the classic dynamic programming table
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// ====================================================== \\
+//	Did You Mean Suggestions
+// ====================================================== \\
+
+// SuggestAuthors returns up to limit author names close to query, closest
+// first, for "did you mean" hints when an author search finds nothing.
+func SuggestAuthors(quotes []Quote, query string, limit int) []string {
+	return suggest(CountAuthors(quotes), query, limit)
+}
+
+// SuggestTags returns up to limit tags close to query, closest first, for
+// "did you mean" hints when a tag search finds nothing.
+func SuggestTags(quotes []Quote, query string, limit int) []string {
+	return suggest(CountTags(quotes), query, limit)
+}
+
+// suggest ranks the names in counts by fuzzyDistance to query, breaking ties
+// by how many quotes use the name. Suggestions are a little more forgiving
+// than fuzzy matching: up to half the query may be wrong.
+func suggest(counts map[string]int, query string, limit int) []string {
+	query = normalize(strings.TrimSpace(query))
+	if query == "" || limit <= 0 {
+		return nil
+	}
+	threshold := max(1, len([]rune(query))/2)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for name := range counts {
+		distance := fuzzyDistance(query, normalize(name))
+		if distance <= threshold {
+			candidates = append(candidates, candidate{name: name, distance: distance})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if counts[a.name] != counts[b.name] {
+			return counts[a.name] > counts[b.name]
+		}
+		return a.name < b.name
+	})
+
+	var names []string
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		names = append(names, candidates[i].name)
+	}
+	return names
+}
@@ -0,0 +1,118 @@
+package quotes
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestEditDistance tests the optimal string alignment distance.
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "abc", b: "", expected: 3},
+		{a: "shakespeare", b: "shakespeare", expected: 0},
+		{a: "shakespear", b: "shakespeare", expected: 1},
+		{a: "rosevelt", b: "roosevelt", expected: 1},
+		{a: "jbos", b: "jobs", expected: 1}, // swapped neighbours count once
+		{a: "kitten", b: "sitting", expected: 3},
+		{a: "camus", b: "camús", expected: 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+// TestSearchByQuoteAuthor_Fuzzy tests typo-tolerant author and tag filters.
+func TestSearchByQuoteAuthor_Fuzzy(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "To be", Author: "William Shakespeare", Tags: []string{"philosophy"}},
+		{ID: 2, Text: "The future", Author: "Eleanor Roosevelt", Tags: []string{"dreams"}},
+		{ID: 3, Text: "Speak softly", Author: "Theodore Roosevelt", Tags: []string{"politics"}},
+		{ID: 4, Text: "Great work", Author: "Steve Jobs", Tags: []string{"work"}},
+	}
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		expectedIDs []int
+	}{
+		{name: "Missing last letter", filter: AuthorFilter{Author: "Shakespear", Fuzzy: true}, expectedIDs: []int{1}},
+		{name: "Missing letter in surname", filter: AuthorFilter{Author: "Rosevelt", Fuzzy: true}, expectedIDs: []int{2, 3}},
+		{name: "Typos across two words", filter: AuthorFilter{Author: "Elenor Rosevelt", Fuzzy: true}, expectedIDs: []int{2}},
+		{name: "Swapped letters", filter: AuthorFilter{Author: "Steve Jbos", Fuzzy: true}, expectedIDs: []int{4}},
+		{name: "Still matches sub-strings", filter: AuthorFilter{Author: "shake", Fuzzy: true}, expectedIDs: []int{1}},
+		{name: "Short names need to be exact", filter: AuthorFilter{Author: "Jx", Fuzzy: true}, expectedIDs: nil},
+		{name: "Too many typos", filter: AuthorFilter{Author: "Shkspr", Fuzzy: true}, expectedIDs: nil},
+		{name: "Without fuzzy typos do not match", filter: AuthorFilter{Author: "Shakespere"}, expectedIDs: nil},
+		{name: "Exact wins over fuzzy", filter: AuthorFilter{Author: "Steve Jbos", Exact: true, Fuzzy: true}, expectedIDs: nil},
+		{name: "Fuzzy tag", filter: TagFilter{Tag: "philosphy", Fuzzy: true}, expectedIDs: []int{1}},
+		{name: "Fuzzy tag plural", filter: TagFilter{Tag: "dream", Fuzzy: true}, expectedIDs: []int{2}},
+	}
+
+	idx := NewIndex(sampleQuotes)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualIDs := quoteIDs(Search(sampleQuotes, tt.filter))
+			if !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("Search() \ngot  = %v, \nwant = %v", actualIDs, tt.expectedIDs)
+			}
+
+			indexedIDs := quoteIDs(idx.Search(tt.filter))
+			if !reflect.DeepEqual(indexedIDs, tt.expectedIDs) {
+				t.Errorf("Index.Search() \ngot  = %v, \nwant = %v", indexedIDs, tt.expectedIDs)
+			}
+		})
+	}
+}
+
+// TestSuggestAuthors tests "did you mean" suggestions.
+func TestSuggestAuthors(t *testing.T) {
+	sampleQuotes := []Quote{
+		{Text: "a", Author: "Seneca"},
+		{Text: "b", Author: "Seneca"},
+		{Text: "c", Author: "Steve Jobs"},
+		{Text: "d", Author: "Oscar Wilde"},
+		{Text: "e", Author: "Oscar Wild"},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		limit    int
+		expected []string
+	}{
+		{name: "Close typo", query: "Sennica", limit: 3, expected: []string{"Seneca"}},
+		{name: "Closest first", query: "oscar wilder", limit: 3, expected: []string{"Oscar Wilde", "Oscar Wild"}},
+		{name: "Limit", query: "oscar wilder", limit: 1, expected: []string{"Oscar Wilde"}},
+		{name: "Nothing close", query: "Nietzsche", limit: 3, expected: nil},
+		{name: "Empty query", query: " ", limit: 3, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SuggestAuthors(sampleQuotes, tt.query, tt.limit)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("SuggestAuthors(%q) = %v; want %v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSuggestTags tests tag suggestions are lowercased tags.
+func TestSuggestTags(t *testing.T) {
+	sampleQuotes := []Quote{
+		{Text: "a", Tags: []string{"Inspiration", "work"}},
+		{Text: "b", Tags: []string{"humor"}},
+	}
+
+	got := SuggestTags(sampleQuotes, "insperation", 3)
+	if !reflect.DeepEqual(got, []string{"inspiration"}) {
+		t.Errorf("SuggestTags() = %v; want [inspiration]", got)
+	}
+}
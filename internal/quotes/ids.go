@@ -0,0 +1,111 @@
+package quotes
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ====================================================== \\
+//	Quote IDs
+// ====================================================== \\
+
+// AssignIDs gives every quote without an ID (ID == 0) a fresh one, in slice
+// order, starting after the largest ID already present. A quote whose ID
+// repeats one seen earlier in the slice is also given a fresh ID, so after the
+// call every ID is unique and positive.
+//
+// Legacy files written before quotes carried IDs are numbered 1..n in file
+// order, so the IDs stay the same from one load to the next even before the
+// file is rewritten.
+func AssignIDs(quoteList []Quote) {
+	assignIDsFrom(NextID(quoteList), quoteList)
+}
+
+// assignIDsFrom is AssignIDs handing out fresh IDs from next on, or from
+// NextID(quoteList) if that is larger. It numbers quotes being added; loading
+// a file uses AssignIDs, so a legacy file keeps its 1..n numbering.
+func assignIDsFrom(next int, quoteList []Quote) {
+	next = max(next, NextID(quoteList))
+	seen := make(map[int]bool, len(quoteList))
+
+	for i := range quoteList {
+		id := quoteList[i].ID
+		if id <= 0 || seen[id] {
+			quoteList[i].ID = next
+			next++
+		}
+		seen[quoteList[i].ID] = true
+	}
+}
+
+// NextID returns one more than the largest ID in quoteList. Quotes added to a
+// file get the larger of that and the file's recorded next ID (see
+// nextFreeID), so the ID of a removed quote is not handed out again.
+func NextID(quoteList []Quote) int {
+	maxID := 0
+	for _, quote := range quoteList {
+		maxID = max(maxID, quote.ID)
+	}
+	return maxID + 1
+}
+
+// IndexOfID returns the slice index of the quote with the given ID, or -1 if
+// there is none.
+func IndexOfID(quoteList []Quote, id int) int {
+	for i, quote := range quoteList {
+		if quote.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// NextIDPath is the file recording the next free ID of filePath, next to it:
+// default.json keeps it in default.json.next-id. It only matters once quotes
+// have been removed; until then NextID of the collection is the same.
+func NextIDPath(filePath string) string {
+	return filePath + ".next-id"
+}
+
+// nextFreeID returns the ID the next quote added to filePath gets: NextID of
+// its quotes in quoteList, or the recorded next ID if a removed quote had a
+// larger one.
+func nextFreeID(quoteList []Quote, filePath string) int {
+	return max(NextID(quoteList), recordedNextID(filePath))
+}
+
+// recordedNextID returns the next ID recorded for filePath, or 0 if there is
+// none. A damaged record is ignored, it only protects removed IDs.
+func recordedNextID(filePath string) int {
+	data, err := os.ReadFile(NextIDPath(filePath))
+	if err != nil {
+		return 0
+	}
+	next, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return next
+}
+
+// recordNextID raises the recorded next ID of filePath to next, so quotes
+// about to be removed keep their IDs to themselves. The caller holds the
+// lock.
+func recordNextID(next int, filePath string) error {
+	if next <= recordedNextID(filePath) {
+		return nil
+	}
+	return writeFileAtomic(NextIDPath(filePath), []byte(strconv.Itoa(next)+"\n"), 0644)
+}
+
+// recordIDsInUse records the IDs of the quotes now in filePath as used,
+// before a change that can drop quotes wholesale, like Undo. A file that
+// cannot be loaded has no IDs to keep. The caller holds the lock.
+func recordIDsInUse(filePath string) error {
+	quoteList, err := loadCollection(filePath)
+	if err != nil {
+		return nil
+	}
+	return recordNextID(NextID(quoteList), filePath)
+}
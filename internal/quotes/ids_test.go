@@ -0,0 +1,180 @@
+package quotes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+//				Test - AssignIDs
+// ====================================================== \\
+
+// TestAssignIDs tests that missing and duplicate IDs are filled in without
+// touching the IDs that are already valid.
+func TestAssignIDs(t *testing.T) {
+	tests := []struct {
+		name        string
+		quotes      []Quote
+		expectedIDs []int
+	}{
+		{
+			name:        "Legacy file without IDs",
+			quotes:      []Quote{{Text: "a"}, {Text: "b"}, {Text: "c"}},
+			expectedIDs: []int{1, 2, 3},
+		},
+		{
+			name:        "Existing IDs are kept",
+			quotes:      []Quote{{ID: 7, Text: "a"}, {ID: 3, Text: "b"}},
+			expectedIDs: []int{7, 3},
+		},
+		{
+			name:        "Missing IDs continue after the largest",
+			quotes:      []Quote{{ID: 4, Text: "a"}, {Text: "b"}, {ID: 2, Text: "c"}, {Text: "d"}},
+			expectedIDs: []int{4, 5, 2, 6},
+		},
+		{
+			name:        "Duplicate IDs are renumbered",
+			quotes:      []Quote{{ID: 1, Text: "a"}, {ID: 1, Text: "b"}, {ID: 2, Text: "c"}},
+			expectedIDs: []int{1, 3, 2},
+		},
+		{
+			name:        "Negative IDs are renumbered",
+			quotes:      []Quote{{ID: -1, Text: "a"}},
+			expectedIDs: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AssignIDs(tt.quotes)
+
+			var actualIDs []int
+			for _, quote := range tt.quotes {
+				actualIDs = append(actualIDs, quote.ID)
+			}
+			if !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("AssignIDs() \ngot  = %v, \nwant = %v", actualIDs, tt.expectedIDs)
+			}
+		})
+	}
+}
+
+// TestIndexOfID tests looking a quote up by its ID.
+func TestIndexOfID(t *testing.T) {
+	quoteList := []Quote{{ID: 5, Text: "a"}, {ID: 2, Text: "b"}}
+
+	if got := IndexOfID(quoteList, 2); got != 1 {
+		t.Errorf("IndexOfID(2) = %d, want 1", got)
+	}
+	if got := IndexOfID(quoteList, 3); got != -1 {
+		t.Errorf("IndexOfID(3) = %d, want -1", got)
+	}
+}
+
+// TestAddNewQuote_AssignsID tests that added quotes get the next ID and that
+// backfilled IDs are persisted by the write.
+func TestAddNewQuote_AssignsID(t *testing.T) {
+	tempDir := t.TempDir()
+	testFilePath := filepath.Join(tempDir, "quotes.json")
+
+	legacyJSON := `[
+	{"text": "Test Quote 1", "author": "Test Author 1"},
+	{"id": 9, "text": "Test Quote 2", "author": "Test Author 2"}
+	]`
+	err := os.WriteFile(testFilePath, []byte(legacyJSON), 0644)
+	if err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	err = AddNewQuote("Test Quote 3", "Test Author 3", []string{"new"}, testFilePath)
+	if err != nil {
+		t.Fatalf("AddNewQuote returned an unexpected error: %v", err)
+	}
+
+	quotes, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+
+	expectedQuotes := []Quote{
+		{ID: 10, Text: "Test Quote 1", Author: "Test Author 1"},
+		{ID: 9, Text: "Test Quote 2", Author: "Test Author 2"},
+		{ID: 11, Text: "Test Quote 3", Author: "Test Author 3", Tags: []string{"new"}},
+	}
+	if !reflect.DeepEqual(quotes, expectedQuotes) {
+		t.Errorf("AddNewQuote wrote incorrect quotes.\nGot: %+v\nWant: %+v", quotes, expectedQuotes)
+	}
+}
+
+// TestNextID_NotReused tests that the IDs of removed and undone quotes are
+// not handed out again, with or without the journal.
+func TestNextID_NotReused(t *testing.T) {
+	for _, isJournaled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("journal %v", isJournaled), func(t *testing.T) {
+			if isJournaled {
+				useJournal(t)
+			}
+			testFilePath := writeTestQuotes(t, testQuotes())
+
+			if _, err := RemoveQuote(3, testFilePath); err != nil {
+				t.Fatalf("RemoveQuote returned an unexpected error: %v", err)
+			}
+			if err := AddNewQuote("Removed top", "", nil, testFilePath); err != nil {
+				t.Fatalf("AddNewQuote returned an unexpected error: %v", err)
+			}
+			if _, err := Undo(testFilePath); err != nil {
+				t.Fatalf("Undo returned an unexpected error: %v", err)
+			}
+			if err := AddNewQuote("Undone add", "", nil, testFilePath); err != nil {
+				t.Fatalf("AddNewQuote returned an unexpected error: %v", err)
+			}
+			if _, err := ImportQuotes([]Quote{{Text: "Imported"}}, false, testFilePath); err != nil {
+				t.Fatalf("ImportQuotes returned an unexpected error: %v", err)
+			}
+
+			quoteList, err := LoadQuotesFromFile(testFilePath)
+			if err != nil {
+				t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+			}
+			expectedIDs := []int{1, 2, 5, 6}
+			if ids := quoteIDs(quoteList); !reflect.DeepEqual(ids, expectedIDs) {
+				t.Errorf("IDs after removing, undoing and adding = %v, want %v", ids, expectedIDs)
+			}
+		})
+	}
+}
+
+// TestNextID_LegacyFile tests that a recorded next ID does not renumber the
+// quotes of a file without IDs, with or without the journal.
+func TestNextID_LegacyFile(t *testing.T) {
+	for _, isJournaled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("journal %v", isJournaled), func(t *testing.T) {
+			if isJournaled {
+				useJournal(t)
+			}
+			testFilePath := filepath.Join(t.TempDir(), "quotes.json")
+			legacyJSON := `[{"text": "one"}, {"text": "two"}, {"text": "three"}]`
+			if err := os.WriteFile(testFilePath, []byte(legacyJSON), 0644); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			if _, err := RemoveQuote(1, testFilePath); err != nil {
+				t.Fatalf("RemoveQuote returned an unexpected error: %v", err)
+			}
+			if err := EditQuote(2, func(quote *Quote) { quote.Text = "TWO" }, testFilePath); err != nil {
+				t.Fatalf("EditQuote returned an unexpected error: %v", err)
+			}
+
+			quoteList, err := LoadQuotesFromFile(testFilePath)
+			if err != nil {
+				t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+			}
+			expected := []Quote{{ID: 2, Text: "TWO"}, {ID: 3, Text: "three"}}
+			if !reflect.DeepEqual(quoteList, expected) {
+				t.Errorf("legacy file after rm 1 and edit 2 \ngot  = %+v, \nwant = %+v", quoteList, expected)
+			}
+		})
+	}
+}
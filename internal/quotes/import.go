@@ -0,0 +1,92 @@
+package quotes
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// ====================================================== \\
+//	Importing
+// ====================================================== \\
+
+// ImportQuotes adds newQuotes to the file at filePath and returns the ones
+// added. Quotes already in the file, or earlier in newQuotes, with the same
+// text and author (compared like searches, see normalize) are skipped, so
+// importing the same file twice adds nothing the second time. Imported quotes
+// get fresh IDs, and the file is locked and written like any other change.
+// With isDryRun the file is left alone and the quotes that would be added
+// are returned.
+func ImportQuotes(newQuotes []Quote, isDryRun bool, filePath string) ([]Quote, error) {
+	if isDryRun {
+		quoteList, err := loadCollection(filePath)
+		if err != nil {
+			return nil, err
+		}
+		_, added := mergeQuotes(quoteList, newQuotes, nextFreeID(quoteList, filePath))
+		return added, nil
+	}
+
+	var added []Quote
+	err := modifyQuotes(filePath, func(quoteList []Quote) ([]Quote, error) {
+		quoteList, added = mergeQuotes(quoteList, newQuotes, nextFreeID(quoteList, filePath))
+		return quoteList, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// mergeQuotes appends the quotes in newQuotes that quoteList lacks, see
+// ImportQuotes, and returns the result and the appended quotes with their
+// new IDs, numbered from nextID on.
+func mergeQuotes(quoteList []Quote, newQuotes []Quote, nextID int) ([]Quote, []Quote) {
+	seen := make(map[string]bool, len(quoteList)+len(newQuotes))
+	for _, quote := range quoteList {
+		seen[quoteKey(quote)] = true
+	}
+
+	var added []Quote
+	for _, quote := range newQuotes {
+		key := quoteKey(quote)
+		if seen[key] || strings.TrimSpace(quote.Text) == "" {
+			continue
+		}
+		seen[key] = true
+
+		quote.ID = 0
+		quoteList = append(quoteList, quote)
+		added = append(added, quote)
+	}
+
+	// number the new quotes now, so added carries their IDs
+	assignIDsFrom(nextID, quoteList)
+	copy(added, quoteList[len(quoteList)-len(added):])
+	return quoteList, added
+}
+
+// quoteKey identifies a quote for duplicate checks: its text and author,
+// normalized and with runs of whitespace collapsed.
+func quoteKey(quote Quote) string {
+	text := strings.Join(strings.Fields(normalize(quote.Text)), " ")
+	author := strings.Join(strings.Fields(normalize(quote.Author)), " ")
+	return text + "\x00" + author
+}
+
+// ReadJSON reads a JSON list of quotes, as found in a quotes file, from r.
+// name labels the input in errors, e.g. its path; a malformed list is a
+// *ParseError.
+func ReadJSON(r io.Reader, name string) ([]Quote, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoteList []Quote
+	if err := json.Unmarshal(data, &quoteList); err != nil {
+		return nil, newParseError(name, data, err)
+	}
+	return quoteList, nil
+}
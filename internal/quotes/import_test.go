@@ -0,0 +1,86 @@
+package quotes
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestImportQuotes tests that imports get fresh IDs and skip duplicates, and
+// that a dry run changes nothing.
+func TestImportQuotes(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	newQuotes := []Quote{
+		{ID: 1, Text: "New", Author: "Someone"},
+		{Text: "great  WORK", Author: "steve jobs"}, // already in the file
+		{Text: "New", Author: "Someone"},            // twice in the import
+		{Text: "  "},
+		{Text: "Newer", Author: "Someone", Tags: []string{"life"}},
+	}
+	expected := []Quote{
+		{ID: 4, Text: "New", Author: "Someone"},
+		{ID: 5, Text: "Newer", Author: "Someone", Tags: []string{"life"}},
+	}
+
+	preview, err := ImportQuotes(newQuotes, true, testFilePath)
+	if err != nil {
+		t.Fatalf("ImportQuotes() dry run returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(preview, expected) {
+		t.Errorf("ImportQuotes() dry run = %v; want %v", preview, expected)
+	}
+	if got := quoteTexts(t, testFilePath); len(got) != len(testQuotes()) {
+		t.Errorf("dry run changed the file to %v", got)
+	}
+
+	added, err := ImportQuotes(newQuotes, false, testFilePath)
+	if err != nil {
+		t.Fatalf("ImportQuotes() returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(added, expected) {
+		t.Errorf("ImportQuotes() = %v; want %v", added, expected)
+	}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, []string{"Great work", "Be yourself", "To be", "New", "Newer"}) {
+		t.Errorf("after ImportQuotes() file holds %v", got)
+	}
+
+	// importing again adds nothing
+	if added, err := ImportQuotes(newQuotes, false, testFilePath); err != nil || len(added) != 0 {
+		t.Errorf("second ImportQuotes() = %v, %v; want nothing added", added, err)
+	}
+}
+
+// TestImportQuotes_NothingNew tests that an import adding nothing leaves the
+// file, its backups and its journal alone, so it does not use up an undo.
+func TestImportQuotes_NothingNew(t *testing.T) {
+	for _, isJournaled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("journal %v", isJournaled), func(t *testing.T) {
+			if isJournaled {
+				useJournal(t)
+			}
+			testFilePath := writeTestQuotes(t, testQuotes())
+			if _, err := ImportQuotes([]Quote{{Text: "New"}}, false, testFilePath); err != nil {
+				t.Fatalf("ImportQuotes() returned an unexpected error: %v", err)
+			}
+			backups, err := ListBackups(testFilePath)
+			if err != nil {
+				t.Fatalf("ListBackups returned an unexpected error: %v", err)
+			}
+			journal, _ := os.ReadFile(JournalPath(testFilePath))
+
+			if _, err := ImportQuotes([]Quote{{Text: "new "}, {Text: "Great work", Author: "Steve Jobs"}}, false, testFilePath); err != nil {
+				t.Fatalf("ImportQuotes() returned an unexpected error: %v", err)
+			}
+
+			if after, _ := ListBackups(testFilePath); len(after) != len(backups) {
+				t.Errorf("ImportQuotes() of nothing new made a backup, %d backups, want %d", len(after), len(backups))
+			}
+			if after, _ := os.ReadFile(JournalPath(testFilePath)); !bytes.Equal(after, journal) {
+				t.Errorf("ImportQuotes() of nothing new changed the journal to\n%s", after)
+			}
+		})
+	}
+}
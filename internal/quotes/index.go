@@ -0,0 +1,391 @@
+package quotes
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ====================================================== \\
+//	Search Index
+// ====================================================== \\
+
+// posting records that a term occurs freq times in the quote at position doc.
+type posting struct {
+	doc  int
+	freq int
+}
+
+// Index is an in-memory inverted index over a quote collection. Build it once
+// with NewIndex after loading and run every search through it; results are
+// the same as the package level SearchBy* functions and Search, without
+// scanning and lowercasing every quote on each query.
+//
+// Each field (tags, authors, text) is only indexed once it is searched a
+// second time; the first search of a field checks every quote, as Search
+// does. A one-off search, like the single one the CLI runs, so costs no more
+// than a scan, and a long-lived Index pays for the fields it is asked about.
+//
+// An Index is safe for concurrent searches. It does not see later changes to
+// the slice it was built from.
+type Index struct {
+	quotes []Quote
+
+	tagField    lazyField
+	tags        map[string][]int // normalized tag -> quote positions
+	authorField lazyField
+	authors     map[string][]int // normalized author -> quote positions
+	textField   lazyField
+	text        fieldIndex // word postings of the quote text
+}
+
+// lazyField tracks the searches of one indexed field.
+type lazyField struct {
+	searches atomic.Int32
+	once     sync.Once
+	built    atomic.Bool
+}
+
+// indexed reports whether a search of the field should use the index,
+// building it with build from the second search on.
+func (l *lazyField) indexed(build func()) bool {
+	if l.searches.Add(1) == 1 {
+		return false
+	}
+	l.once.Do(func() {
+		build()
+		l.built.Store(true)
+	})
+	return true
+}
+
+// isBuilt reports whether the field's index is ready, without counting a
+// search.
+func (l *lazyField) isBuilt() bool {
+	return l.built.Load()
+}
+
+// NewIndex returns an Index over quoteList. The fields are indexed as
+// searches need them.
+func NewIndex(quoteList []Quote) *Index {
+	return &Index{quotes: quoteList}
+}
+
+// buildTags indexes the quote tags.
+func (idx *Index) buildTags() {
+	idx.tags = make(map[string][]int)
+	seenTags := make(map[string]bool) // reused for every quote
+	for doc, quote := range idx.quotes {
+		clear(seenTags)
+		for _, quoteTag := range quote.Tags {
+			tag := normalize(quoteTag)
+			if !seenTags[tag] {
+				seenTags[tag] = true
+				idx.tags[tag] = append(idx.tags[tag], doc)
+			}
+		}
+	}
+	// quotes are visited in order, so every position list is already sorted
+}
+
+// buildAuthors indexes the quote authors.
+func (idx *Index) buildAuthors() {
+	idx.authors = make(map[string][]int)
+	for doc, quote := range idx.quotes {
+		author := normalize(quote.Author)
+		idx.authors[author] = append(idx.authors[author], doc)
+	}
+}
+
+// buildText indexes the words of the quote text.
+func (idx *Index) buildText() {
+	idx.text = newFieldIndex(textBoost, len(idx.quotes))
+	freqs := make(map[string]int) // reused for every quote
+	for doc, quote := range idx.quotes {
+		idx.text.add(doc, tokenize(quote.Text), freqs)
+	}
+	idx.text.finish()
+}
+
+// LoadIndexFromFile loads the quotes at filepath (see LoadQuotesFromFile) and
+// builds an Index over them.
+func LoadIndexFromFile(filepath string) (*Index, error) {
+	quoteList, err := LoadQuotesFromFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(quoteList), nil
+}
+
+// Quotes returns the quotes the index was built from.
+func (idx *Index) Quotes() []Quote {
+	return idx.quotes
+}
+
+// SearchByQuoteTag is the indexed SearchByQuoteTag.
+func (idx *Index) SearchByQuoteTag(targetTag string, isExact bool) []Quote {
+	return idx.Search(TagFilter{Tag: targetTag, Exact: isExact})
+}
+
+// SearchByQuoteAuthor is the indexed SearchByQuoteAuthor.
+func (idx *Index) SearchByQuoteAuthor(authorName string, isExact bool) []Quote {
+	return idx.Search(AuthorFilter{Author: authorName, Exact: isExact})
+}
+
+// SearchByText is the indexed SearchByText.
+func (idx *Index) SearchByText(query string, isExact bool, mode TermMode) []Quote {
+	return idx.Search(TextFilter{Text: query, Exact: isExact, Mode: mode})
+}
+
+// Search returns the quotes that match filter, in their original order, the
+// same as Search(idx.Quotes(), filter). Tag, author and text filters and any
+// And/Or/Not combination of them are answered from the index; other Filter
+// implementations fall back to checking each quote.
+func (idx *Index) Search(filter Filter) []Quote {
+	var matchingQuotes []Quote
+
+	for _, doc := range idx.docs(filter) {
+		matchingQuotes = append(matchingQuotes, idx.quotes[doc])
+	}
+
+	return matchingQuotes
+}
+
+// docs returns the sorted positions of the quotes matching filter.
+func (idx *Index) docs(filter Filter) []int {
+	switch f := filter.(type) {
+	case TagFilter:
+		if !idx.tagField.indexed(idx.buildTags) {
+			return idx.scanDocs(filter)
+		}
+		return lookupKeys(idx.tags, normalize(strings.TrimSpace(f.Tag)), f.Exact, f.Fuzzy)
+
+	case AuthorFilter:
+		if !idx.authorField.indexed(idx.buildAuthors) {
+			return idx.scanDocs(filter)
+		}
+		return lookupKeys(idx.authors, normalize(strings.TrimSpace(f.Author)), f.Exact, f.Fuzzy)
+
+	case TextFilter:
+		terms := tokenize(f.Text)
+		if len(terms) == 0 {
+			return nil
+		}
+		if !idx.textField.indexed(idx.buildText) {
+			return idx.scanDocs(filter)
+		}
+
+		var result []int
+		for i, term := range terms {
+			termDocs := idx.termDocs(term, f.Exact)
+			switch {
+			case i == 0:
+				result = termDocs
+			case f.Mode == AnyTerm:
+				result = unionDocs(result, termDocs)
+			default:
+				result = intersectDocs(result, termDocs)
+			}
+		}
+
+		// the index knows which quotes have every word, not where they are
+		if f.Mode == Phrase {
+			result = matchDocs(idx.quotes, result, f)
+		}
+		return result
+
+	case AndFilter:
+		if len(f) == 0 {
+			return idx.allDocs()
+		}
+
+		// the rest only has to be checked against what the first one found
+		result := idx.docs(f[0])
+		for _, sub := range f[1:] {
+			result = matchDocs(idx.quotes, result, sub)
+		}
+		return result
+
+	case OrFilter:
+		var result []int
+		for _, sub := range f {
+			result = unionDocs(result, idx.docs(sub))
+		}
+		return result
+
+	case NotFilter:
+		return subtractDocs(idx.allDocs(), idx.docs(f.Filter))
+	}
+
+	// unknown filter, check every quote
+	return idx.scanDocs(filter)
+}
+
+// scanDocs returns the sorted positions of the quotes matching filter by
+// checking every quote.
+func (idx *Index) scanDocs(filter Filter) []int {
+	var result []int
+	for doc, quote := range idx.quotes {
+		if filter.Match(quote) {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+// matchDocs returns the positions in docs whose quote matches filter.
+func matchDocs(quoteList []Quote, docs []int, filter Filter) []int {
+	var result []int
+	for _, doc := range docs {
+		if filter.Match(quoteList[doc]) {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+// termDocs returns the sorted positions of the quotes containing term as a
+// whole word, or when not isExact as part of a word.
+func (idx *Index) termDocs(term string, isExact bool) []int {
+	var result []int
+
+	if isExact {
+		for _, p := range idx.text.postings[term] {
+			result = append(result, p.doc)
+		}
+		return result
+	}
+
+	for key, postings := range idx.text.postings {
+		if strings.Contains(key, term) {
+			for _, p := range postings {
+				result = append(result, p.doc)
+			}
+		}
+	}
+	sort.Ints(result)
+	return dedupeDocs(result)
+}
+
+// allDocs returns every quote position.
+func (idx *Index) allDocs() []int {
+	docs := make([]int, len(idx.quotes))
+	for i := range docs {
+		docs[i] = i
+	}
+	return docs
+}
+
+// lookupKeys returns the positions stored under target (isExact), or under
+// every key matching target as matchField does. An empty target matches
+// nothing. The result may share memory with keys, so it must not be modified.
+func lookupKeys(keys map[string][]int, target string, isExact bool, isFuzzy bool) []int {
+	if target == "" {
+		return nil
+	}
+	if isExact {
+		return keys[target]
+	}
+
+	var matched [][]int
+	for key, docs := range keys {
+		if matchField(key, target, false, isFuzzy) {
+			matched = append(matched, docs)
+		}
+	}
+	if len(matched) == 1 {
+		return matched[0]
+	}
+
+	// merge in one go rather than pairwise, since substring and fuzzy matches
+	// can hit many keys
+	var result []int
+	for _, docs := range matched {
+		result = append(result, docs...)
+	}
+	sort.Ints(result)
+	return dedupeDocs(result)
+}
+
+// ====================================================== \\
+//	Sorted Position Set Helpers
+// ====================================================== \\
+
+// intersectDocs returns the positions in both a and b.
+func intersectDocs(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// unionDocs returns the positions in a or b. The result may share memory
+// with a or b, so it must not be modified.
+func unionDocs(a, b []int) []int {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+
+	result := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		case a[i] > b[j]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+// subtractDocs returns the positions in a that are not in b.
+func subtractDocs(a, b []int) []int {
+	var result []int
+	j := 0
+	for _, doc := range a {
+		for j < len(b) && b[j] < doc {
+			j++
+		}
+		if j < len(b) && b[j] == doc {
+			continue
+		}
+		result = append(result, doc)
+	}
+	return result
+}
+
+// dedupeDocs removes repeats from sorted, in place.
+func dedupeDocs(sorted []int) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, doc := range sorted[1:] {
+		if doc != result[len(result)-1] {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package quotes
+
+import (
+	"reflect"
+	"testing"
+)
+
+//				Test - Index
+// ====================================================== \\
+
+// TestIndex_MatchesLinearSearch tests that every indexed search returns the
+// same quotes, in the same order, as the linear Search over the same filter,
+// both while the index still scans a field and once it has indexed it.
+func TestIndex_MatchesLinearSearch(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "The only way to do great work is to love what you do.", Author: "Steve Jobs", Tags: []string{"inspiration", "work"}},
+		{ID: 2, Text: "Innovation distinguishes between a leader and a follower.", Author: "Steve Jobs", Tags: []string{"innovation", "Work"}},
+		{ID: 3, Text: "Great things are done by a series of small things.", Author: "Vincent van Gogh", Tags: []string{"work", "art"}},
+		{ID: 4, Text: "Be yourself; everyone else is already taken.", Author: "Oscar Wilde", Tags: []string{"identity", "humor"}},
+		{ID: 5, Text: "Workers of the world, unite!", Author: "Karl Marx", Tags: nil},
+		{ID: 6, Text: "Love all, trust a few, do wrong to none.", Author: "", Tags: []string{"love", "love"}},
+	}
+	filters := map[string]Filter{
+		"Exact tag":                 TagFilter{Tag: "work", Exact: true},
+		"Substring tag":             TagFilter{Tag: "in"},
+		"Tag with spaces":           TagFilter{Tag: "  ART "},
+		"Empty tag":                 TagFilter{Tag: ""},
+		"Exact author":              AuthorFilter{Author: "steve jobs", Exact: true},
+		"Substring author":          AuthorFilter{Author: "o"},
+		"Text all terms":            TextFilter{Text: "great love"},
+		"Text any term":             TextFilter{Text: "great love", Mode: AnyTerm},
+		"Text whole word":           TextFilter{Text: "work", Exact: true},
+		"Text substring":            TextFilter{Text: "work"},
+		"Text empty":                TextFilter{Text: "!!"},
+		"And":                       AndFilter{TagFilter{Tag: "work"}, AuthorFilter{Author: "jobs"}, TextFilter{Text: "great"}},
+		"Empty and":                 AndFilter{},
+		"Or":                        OrFilter{AuthorFilter{Author: "wilde"}, TagFilter{Tag: "art"}},
+		"Not":                       NotFilter{TagFilter{Tag: "work"}},
+		"Nested":                    OrFilter{AndFilter{TagFilter{Tag: "work"}, NotFilter{AuthorFilter{Author: "jobs"}}}, TextFilter{Text: "unite"}},
+		"Unknown filter type":       matchAllFilter{},
+		"Unknown filter inside and": AndFilter{matchAllFilter{}, TagFilter{Tag: "humor"}},
+	}
+
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			expected := Search(sampleQuotes, filter)
+			idx := NewIndex(sampleQuotes)
+
+			for _, search := range []string{"first", "second"} {
+				actual := idx.Search(filter)
+
+				if !reflect.DeepEqual(actual, expected) {
+					t.Errorf("Index.Search() %s search \ngot  = %v, \nwant = %v", search, quoteIDs(actual), quoteIDs(expected))
+				}
+			}
+		})
+	}
+}
+
+// matchAllFilter is a Filter the index does not know about.
+type matchAllFilter struct{}
+
+func (matchAllFilter) Match(Quote) bool { return true }
@@ -0,0 +1,479 @@
+package quotes
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// ====================================================== \\
+//	Change Journal
+// ====================================================== \\
+
+// UseJournal makes changes append a line per added, updated or deleted quote
+// to a journal next to the quotes file (see JournalPath) instead of rewriting
+// the whole file. The loader replays the journal whether or not UseJournal is
+// set, and once it holds JournalCompactAfter changes it is folded back into
+// the quotes file.
+var UseJournal = false
+
+// JournalCompactAfter is how many changes a journal collects before it is
+// compacted into the quotes file.
+var JournalCompactAfter = 500
+
+// JournalOp is what a journal entry did to its quote.
+type JournalOp string
+
+const (
+	JournalAdd    JournalOp = "add"
+	JournalUpdate JournalOp = "update"
+	JournalDelete JournalOp = "delete"
+)
+
+// JournalEntry is one line of a journal. Entries written by the same change
+// share their Time.
+type JournalEntry struct {
+	Op    JournalOp `json:"op"`
+	ID    int       `json:"id"`
+	Quote *Quote    `json:"quote,omitempty"` // the quote after an add or update
+	Old   *Quote    `json:"old,omitempty"`   // the quote before an update or delete
+	Time  time.Time `json:"time"`
+	User  string    `json:"user,omitempty"`
+
+	// NextID is NextID of the collection after the change, so adding a quote
+	// does not need to load the collection to number it.
+	NextID int `json:"next_id"`
+}
+
+// journalHeader is the first line of a journal. It ties the journal to the
+// exact quotes file it applies to: once the quotes file is rewritten (by a
+// compaction, a restore or a change made without the journal) the old journal
+// no longer matches and is ignored, so its changes are never applied twice.
+type journalHeader struct {
+	Snapshot string `json:"snapshot"` // sha256 of the quotes file
+	NextID   int    `json:"next_id"`  // NextID of the quotes file
+}
+
+// JournalPath is the journal of filePath: default.json keeps its journal in
+// default.json.journal.
+func JournalPath(filePath string) string {
+	return filePath + ".journal"
+}
+
+// journal is the journal of one quotes file, as read from disk.
+type journal struct {
+	path    string
+	header  journalHeader
+	entries []JournalEntry
+	isFresh bool // missing or stale on disk, the header still has to be written
+}
+
+// snapshotHash identifies the contents of a quotes file.
+func snapshotHash(snapshot []byte) string {
+	sum := sha256.Sum256(snapshot)
+	return hex.EncodeToString(sum[:])
+}
+
+// readJournal reads the journal of filePath, whose current contents are
+// snapshot. A missing journal, or one written for different contents, is
+// empty and fresh. A last line cut short by a crash is ignored.
+func readJournal(filePath string, snapshot []byte) (*journal, error) {
+	j := &journal{
+		path:    JournalPath(filePath),
+		header:  journalHeader{Snapshot: snapshotHash(snapshot)},
+		isFresh: true,
+	}
+
+	file, err := os.Open(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal %q: %w", j.path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read journal %q: %w", j.path, err)
+		}
+		isTorn := err == io.EOF // no newline, the write never finished
+		if len(bytes.TrimSpace(line)) == 0 || isTorn {
+			return j, nil
+		}
+
+		if lineNumber == 1 {
+			var header journalHeader
+			if err := json.Unmarshal(line, &header); err != nil {
+				return nil, newJournalParseError(j.path, lineNumber, line, err)
+			}
+			if header.Snapshot != j.header.Snapshot {
+				return j, nil // stale, its changes are already in the quotes file
+			}
+			j.header = header
+			j.isFresh = false
+			continue
+		}
+
+		var entry JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, newJournalParseError(j.path, lineNumber, line, err)
+		}
+		j.entries = append(j.entries, entry)
+	}
+}
+
+// newJournalParseError is the ParseError for line lineNumber of a journal.
+func newJournalParseError(path string, lineNumber int, line []byte, err error) *ParseError {
+	parseErr := newParseError(path, line, err)
+	if parseErr.Line != 0 {
+		parseErr.Line = lineNumber
+	}
+	return parseErr
+}
+
+// nextID is the ID the next added quote gets, see NextID.
+func (j *journal) nextID() int {
+	if len(j.entries) == 0 {
+		return j.header.NextID
+	}
+	return j.entries[len(j.entries)-1].NextID
+}
+
+// append writes entries to the end of the journal and syncs it. A fresh
+// journal is written from scratch instead.
+func (j *journal) append(entries []JournalEntry) error {
+	if j.isFresh {
+		j.entries = append(j.entries, entries...)
+		return j.rewrite()
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(buf.Bytes())
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	j.entries = append(j.entries, entries...)
+	return nil
+}
+
+// rewrite replaces the journal on disk with the header and j.entries.
+func (j *journal) rewrite() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(j.header); err != nil {
+		return err
+	}
+	for _, entry := range j.entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFileAtomic(j.path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	j.isFresh = false
+	return nil
+}
+
+// lastChange returns the index of the first entry of the last change in
+// entries, which must not be empty.
+func lastChange(entries []JournalEntry) int {
+	last := len(entries)
+	start := last - 1
+	for start > 0 && entries[start-1].Time.Equal(entries[last-1].Time) {
+		start--
+	}
+	return start
+}
+
+// dropLastChange rewrites the journal without the entries of its last
+// change and returns them. The journal must not be empty.
+func (j *journal) dropLastChange() ([]JournalEntry, error) {
+	start := lastChange(j.entries)
+	dropped := j.entries[start:]
+
+	j.entries = j.entries[:start:start]
+	if err := j.rewrite(); err != nil {
+		return nil, err
+	}
+	return dropped, nil
+}
+
+// replayJournal applies entries to quoteList in order. Adds and updates set
+// the quote with the entry's ID, wherever it is, and deletes remove it, so
+// replaying a change that is already applied changes nothing.
+func replayJournal(quoteList []Quote, entries []JournalEntry) []Quote {
+	if len(entries) == 0 {
+		return quoteList
+	}
+
+	positions := make(map[int]int, len(quoteList))
+	for i, quote := range quoteList {
+		positions[quote.ID] = i
+	}
+	deleted := make(map[int]bool)
+
+	for _, entry := range entries {
+		switch entry.Op {
+		case JournalAdd, JournalUpdate:
+			if entry.Quote == nil {
+				continue
+			}
+			quote := *entry.Quote
+			quote.ID = entry.ID
+			if i, ok := positions[entry.ID]; ok {
+				quoteList[i] = quote
+			} else {
+				positions[entry.ID] = len(quoteList)
+				quoteList = append(quoteList, quote)
+			}
+		case JournalDelete:
+			if i, ok := positions[entry.ID]; ok {
+				deleted[i] = true
+				delete(positions, entry.ID)
+			}
+		}
+	}
+
+	kept := quoteList[:0]
+	for i, quote := range quoteList {
+		if !deleted[i] {
+			kept = append(kept, quote)
+		}
+	}
+	return kept
+}
+
+// journalChanges returns the entries turning before into after, matching
+// quotes by ID: deletes first, then adds and updates in after's order.
+func journalChanges(before []Quote, after []Quote, nextID int) []JournalEntry {
+	now := time.Now().UTC()
+	who := currentUser()
+	entry := func(op JournalOp, id int, quote *Quote, old *Quote) JournalEntry {
+		return JournalEntry{Op: op, ID: id, Quote: quote, Old: old, Time: now, User: who, NextID: nextID}
+	}
+
+	afterIDs := make(map[int]bool, len(after))
+	for _, quote := range after {
+		afterIDs[quote.ID] = true
+	}
+	beforeByID := make(map[int]Quote, len(before))
+	var entries []JournalEntry
+	for _, quote := range before {
+		beforeByID[quote.ID] = quote
+		if !afterIDs[quote.ID] {
+			entries = append(entries, entry(JournalDelete, quote.ID, nil, &quote))
+		}
+	}
+
+	for _, quote := range after {
+		old, existed := beforeByID[quote.ID]
+		switch {
+		case !existed:
+			entries = append(entries, entry(JournalAdd, quote.ID, &quote, nil))
+		case !reflect.DeepEqual(old, quote):
+			entries = append(entries, entry(JournalUpdate, quote.ID, &quote, &old))
+		}
+	}
+
+	return entries
+}
+
+// currentUser names who is making a change, for the journal.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// ReadJournal returns the changes in the journal of filePath that are not
+// compacted into it yet, oldest first.
+func ReadJournal(filePath string) ([]JournalEntry, error) {
+	snapshot, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quotes file %q: %w", filePath, err)
+	}
+	j, err := readJournal(filePath, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return j.entries, nil
+}
+
+// journalQuotes records the change from before to after in the journal of
+// filePath, compacting it if it is full. The caller holds the lock.
+func journalQuotes(before []Quote, after []Quote, filePath string) error {
+	entries := journalChanges(before, after, nextFreeID(after, filePath))
+	if len(entries) == 0 {
+		return nil
+	}
+
+	snapshot, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read quotes file %q: %w", filePath, err)
+	}
+	j, err := readJournal(filePath, snapshot)
+	if err != nil {
+		return err
+	}
+	if j.isFresh {
+		j.header.NextID = nextFreeID(before, filePath)
+	}
+
+	return appendJournal(j, entries, snapshot, filePath)
+}
+
+// journalAddQuote records quote as added to filePath, giving it the next free
+// ID, without loading the collection unless the journal is fresh. The caller
+// holds the lock.
+func journalAddQuote(quote Quote, filePath string) error {
+	snapshot, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read quotes file %q: %w", filePath, err)
+	}
+	j, err := readJournal(filePath, snapshot)
+	if err != nil {
+		return err
+	}
+	if j.isFresh {
+		quoteList, err := loadCollection(filePath)
+		if err != nil {
+			return err
+		}
+		j.header.NextID = nextFreeID(quoteList, filePath)
+	}
+
+	quote.ID = max(j.nextID(), recordedNextID(filePath))
+	entry := JournalEntry{
+		Op:     JournalAdd,
+		ID:     quote.ID,
+		Quote:  &quote,
+		Time:   time.Now().UTC(),
+		User:   currentUser(),
+		NextID: quote.ID + 1,
+	}
+	return appendJournal(j, []JournalEntry{entry}, snapshot, filePath)
+}
+
+// appendJournal appends entries to j, the journal of filePath whose quotes
+// file holds snapshot, and compacts it once it is full.
+func appendJournal(j *journal, entries []JournalEntry, snapshot []byte, filePath string) error {
+	if err := j.append(entries); err != nil {
+		return &WriteError{Path: j.path, Err: err}
+	}
+	if len(j.entries) < JournalCompactAfter {
+		return nil
+	}
+	return compactJournal(snapshot, filePath)
+}
+
+// CompactJournal folds the journal of filePath into the quotes file. The
+// journal is archived next to it, see archiveJournal.
+func CompactJournal(filePath string) error {
+	unlock, err := lockQuotesFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	snapshot, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read quotes file %q: %w", filePath, err)
+	}
+	return compactJournal(snapshot, filePath)
+}
+
+// compactJournal writes the quotes of filePath with its journal applied back
+// to filePath. The collection as it was before the journal's last change is
+// kept as a backup, so undo after compacting takes back just that change and
+// the other journaled changes stay. The caller holds the lock.
+func compactJournal(snapshot []byte, filePath string) error {
+	j, err := readJournal(filePath, snapshot)
+	if err != nil {
+		return err
+	}
+	if len(j.entries) == 0 {
+		return nil
+	}
+
+	var quoteList []Quote
+	if err := json.Unmarshal(snapshot, &quoteList); err != nil {
+		return newParseError(filePath, snapshot, err)
+	}
+	AssignIDs(quoteList)
+	previous := replayJournal(slices.Clone(quoteList), j.entries[:lastChange(j.entries)])
+	quoteList = replayJournal(quoteList, j.entries)
+
+	data, err := json.MarshalIndent(previous, "", "\t")
+	if err != nil {
+		return &WriteError{Path: filePath, Err: err}
+	}
+	if err := saveBackup(filePath, data); err != nil {
+		return &WriteError{Path: filePath, Err: fmt.Errorf("backing up: %w", err)}
+	}
+	return writeQuotes(quoteList, filePath)
+}
+
+// archiveJournal moves the journal of filePath aside once the quotes file
+// holds its changes, so it stays on disk as a record of who changed what:
+// default.json.journal becomes default.json.journal.<time>. A journal without
+// changes is just deleted. A journal left behind is harmless, see
+// journalHeader.
+func archiveJournal(filePath string) {
+	path := JournalPath(filePath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if bytes.Count(data, []byte("\n")) <= 1 { // only the header
+		os.Remove(path)
+		return
+	}
+
+	// like backups, a journal archived in the same nanosecond as another
+	// moves on to the next free name
+	for now := time.Now().UTC(); ; now = now.Add(time.Nanosecond) {
+		archivePath := path + "." + now.Format(backupTimeFormat)
+		if _, err := os.Lstat(archivePath); errors.Is(err, fs.ErrNotExist) {
+			os.Rename(path, archivePath)
+			return
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package quotes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// useJournal turns UseJournal on for the rest of the test.
+func useJournal(t *testing.T) {
+	t.Helper()
+	old := UseJournal
+	UseJournal = true
+	t.Cleanup(func() { UseJournal = old })
+}
+
+// TestJournal_Replay tests that journaled changes leave the quotes file alone
+// and show up when it is loaded.
+func TestJournal_Replay(t *testing.T) {
+	useJournal(t)
+	testFilePath := writeTestQuotes(t, testQuotes())
+	snapshot, err := os.ReadFile(testFilePath)
+	if err != nil {
+		t.Fatalf("failed to read test file: %v", err)
+	}
+
+	if err := AddNewQuote("New", "Someone", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	if _, err := RemoveQuote(1, testFilePath); err != nil {
+		t.Fatalf("RemoveQuote() returned an unexpected error: %v", err)
+	}
+	if err := ReplaceTags(2, []string{"wit"}, testFilePath); err != nil {
+		t.Fatalf("ReplaceTags() returned an unexpected error: %v", err)
+	}
+
+	if data, _ := os.ReadFile(testFilePath); string(data) != string(snapshot) {
+		t.Error("journaled changes rewrote the quotes file")
+	}
+
+	entries, err := ReadJournal(testFilePath)
+	if err != nil {
+		t.Fatalf("ReadJournal() returned an unexpected error: %v", err)
+	}
+	var ops []JournalOp
+	for _, entry := range entries {
+		ops = append(ops, entry.Op)
+	}
+	if expected := []JournalOp{JournalAdd, JournalDelete, JournalUpdate}; !reflect.DeepEqual(ops, expected) {
+		t.Errorf("journal ops = %v; want %v", ops, expected)
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	expected := []Quote{
+		{ID: 2, Text: "Be yourself", Author: "Oscar Wilde", Tags: []string{"wit"}},
+		{ID: 3, Text: "To be", Author: "William Shakespeare", Tags: []string{"drama"}},
+		{ID: 4, Text: "New", Author: "Someone"},
+	}
+	if !reflect.DeepEqual(quoteList, expected) {
+		t.Errorf("LoadQuotesFromFile() = %v; want %v", quoteList, expected)
+	}
+}
+
+// TestReplayJournal tests applying entries, including replaying ones that are
+// already applied.
+func TestReplayJournal(t *testing.T) {
+	updated := Quote{Text: "Be yourself!", Author: "Oscar Wilde"}
+	added := Quote{Text: "New"}
+	entries := []JournalEntry{
+		{Op: JournalUpdate, ID: 2, Quote: &updated},
+		{Op: JournalDelete, ID: 1},
+		{Op: JournalAdd, ID: 4, Quote: &added},
+		{Op: JournalDelete, ID: 42}, // already gone
+	}
+
+	tests := []struct {
+		name     string
+		start    []Quote
+		expected []int
+	}{
+		{name: "Fresh", start: testQuotes(), expected: []int{2, 3, 4}},
+		{name: "Already applied", start: replayJournal(testQuotes(), entries), expected: []int{2, 3, 4}},
+		{name: "Empty start", start: nil, expected: []int{2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replayJournal(tt.start, entries)
+			if ids := quoteIDs(got); !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("replayJournal() ids = %v; want %v", ids, tt.expected)
+			}
+			if got[0].Text != "Be yourself!" {
+				t.Errorf("replayJournal() quote 2 = %q; want the update", got[0].Text)
+			}
+		})
+	}
+}
+
+// TestJournal_StaleAndTorn tests that a journal written for other contents of
+// the quotes file, and a last line cut short, are ignored.
+func TestJournal_StaleAndTorn(t *testing.T) {
+	useJournal(t)
+	testFilePath := writeTestQuotes(t, testQuotes())
+	if err := AddNewQuote("New", "Someone", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+
+	// a crash halfway through appending the next change
+	file, err := os.OpenFile(JournalPath(testFilePath), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("failed to open journal: %v", err)
+	}
+	file.WriteString(`{"op":"add","id":5,"quo`)
+	file.Close()
+
+	expected := []string{"Great work", "Be yourself", "To be", "New"}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+		t.Errorf("with a torn journal file holds %v; want %v", got, expected)
+	}
+
+	// a crash between compacting and removing the journal
+	journalData, err := os.ReadFile(JournalPath(testFilePath))
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if err := CompactJournal(testFilePath); err != nil {
+		t.Fatalf("CompactJournal() returned an unexpected error: %v", err)
+	}
+	if err := os.WriteFile(JournalPath(testFilePath), journalData, 0644); err != nil {
+		t.Fatalf("failed to restore journal: %v", err)
+	}
+
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+		t.Errorf("with a stale journal file holds %v; want %v", got, expected)
+	}
+	if entries, _ := ReadJournal(testFilePath); len(entries) != 0 {
+		t.Errorf("ReadJournal() of a stale journal = %v; want none", entries)
+	}
+}
+
+// TestJournal_Compaction tests that a full journal is folded into the quotes
+// file and archived, keeping the collection from before its last change as a
+// backup.
+func TestJournal_Compaction(t *testing.T) {
+	useJournal(t)
+	defer func(old int) { JournalCompactAfter = old }(JournalCompactAfter)
+	JournalCompactAfter = 3
+
+	testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+	for _, text := range []string{"v2", "v3"} {
+		if err := AddNewQuote(text, "", nil, testFilePath); err != nil {
+			t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+		}
+	}
+	if _, err := os.Stat(JournalPath(testFilePath)); err != nil {
+		t.Fatalf("journal missing before it is full: %v", err)
+	}
+
+	if err := AddNewQuote("v4", "", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	if _, err := os.Stat(JournalPath(testFilePath)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("journal left behind after compaction: %v", err)
+	}
+	archived, _ := filepath.Glob(JournalPath(testFilePath) + ".*")
+	if len(archived) != 1 {
+		t.Fatalf("archived journals = %v; want one", archived)
+	}
+	if data, _ := os.ReadFile(archived[0]); strings.Count(string(data), "\n") != 4 {
+		t.Errorf("archived journal holds\n%s\nwant the header and 3 changes", data)
+	}
+
+	UseJournal = false
+	expected := []string{"v1", "v2", "v3", "v4"}
+	if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+		t.Errorf("after compaction file holds %v; want %v", got, expected)
+	}
+
+	backups, err := ListBackups(testFilePath)
+	if err != nil || len(backups) == 0 {
+		t.Fatalf("ListBackups() = %v, %v; want the file from before compaction", backups, err)
+	}
+	if got := quoteTexts(t, backups[0].Path); !reflect.DeepEqual(got, []string{"v1", "v2", "v3"}) {
+		t.Errorf("newest backup holds %v; want [v1 v2 v3]", got)
+	}
+}
+
+// TestJournal_UndoAfterCompaction tests that undo after a compaction, made
+// automatically or by hand, takes back only the last change.
+func TestJournal_UndoAfterCompaction(t *testing.T) {
+	useJournal(t)
+	defer func(old int) { JournalCompactAfter = old }(JournalCompactAfter)
+	JournalCompactAfter = 3
+
+	tests := []struct {
+		name     string
+		adds     []string
+		compact  bool
+		expected []string
+	}{
+		{name: "Automatic", adds: []string{"v2", "v3", "v4"}, expected: []string{"v1", "v2", "v3"}},
+		{name: "By hand", adds: []string{"v2", "v3"}, compact: true, expected: []string{"v1", "v2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+			for _, text := range tt.adds {
+				if err := AddNewQuote(text, "", nil, testFilePath); err != nil {
+					t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+				}
+			}
+			if tt.compact {
+				if err := CompactJournal(testFilePath); err != nil {
+					t.Fatalf("CompactJournal() returned an unexpected error: %v", err)
+				}
+			}
+			if entries, _ := ReadJournal(testFilePath); len(entries) != 0 {
+				t.Fatalf("journal holds %d changes after compaction", len(entries))
+			}
+
+			if _, err := Undo(testFilePath); err != nil {
+				t.Fatalf("Undo() returned an unexpected error: %v", err)
+			}
+			if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("after Undo() file holds %v; want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestJournal_Undo tests that undo drops journaled changes one at a time
+// before falling back to backups.
+func TestJournal_Undo(t *testing.T) {
+	useJournal(t)
+	testFilePath := writeTestQuotes(t, []Quote{{Text: "v1"}})
+	if err := WriteQuoteToFile([]Quote{{Text: "v2"}}, testFilePath); err != nil {
+		t.Fatalf("WriteQuoteToFile() returned an unexpected error: %v", err)
+	}
+	if err := AddNewQuote("Added", "", nil, testFilePath); err != nil {
+		t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+	}
+	if _, err := RemoveQuotes([]int{1, 2}, testFilePath); err != nil {
+		t.Fatalf("RemoveQuotes() returned an unexpected error: %v", err)
+	}
+
+	steps := [][]string{
+		{"v2", "Added"}, // both removals undone together
+		{"v2"},          // add undone
+		{"v1"},          // back to the backup
+	}
+	for _, expected := range steps {
+		if _, err := Undo(testFilePath); err != nil {
+			t.Fatalf("Undo() returned an unexpected error: %v", err)
+		}
+		if got := quoteTexts(t, testFilePath); !reflect.DeepEqual(got, expected) {
+			t.Errorf("after Undo() file holds %v; want %v", got, expected)
+		}
+	}
+}
+
+// TestJournal_ConcurrentAdds tests that concurrent journaled adds are all kept
+// with distinct IDs.
+func TestJournal_ConcurrentAdds(t *testing.T) {
+	useJournal(t)
+	const writers = 20
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- AddNewQuote(fmt.Sprintf("Quote %d", i), "Writer", nil, testFilePath)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+		}
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	if expected := len(testQuotes()) + writers; len(quoteList) != expected {
+		t.Errorf("got %d quotes after concurrent adds; want %d", len(quoteList), expected)
+	}
+	seen := make(map[int]bool)
+	for _, quote := range quoteList {
+		if seen[quote.ID] {
+			t.Errorf("id %d used twice", quote.ID)
+		}
+		seen[quote.ID] = true
+	}
+}
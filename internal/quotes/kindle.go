@@ -0,0 +1,141 @@
+package quotes
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ====================================================== \\
+//	Kindle Clippings
+// ====================================================== \\
+
+// kindleSeparator ends every entry of a Kindle "My Clippings.txt".
+const kindleSeparator = "=========="
+
+// kindleLocation finds the location range in the metadata line of a
+// clipping, e.g. "Location 1234-1240", in the languages Kindles ship with.
+var kindleLocation = regexp.MustCompile(`(?i)(?:location|loc\.|position|emplacement|posición|posizione)\s+(\d+)(?:-(\d+))?`)
+
+// kindleNote marks the metadata line of a note or bookmark, which are not
+// quotes from the book.
+var kindleNote = regexp.MustCompile(`(?i)\b(?:note|nota|notiz|bookmark|lesezeichen|signet|marcador|segnalibro)\b`)
+
+// clipping is a highlight read from a Kindle clippings file.
+type clipping struct {
+	quote      Quote
+	book       string
+	start, end int // location range, 0 when the clipping has none
+}
+
+// ReadKindleClippings reads the highlights in a Kindle "My Clippings.txt".
+// Each entry is a "Title (Author)" line, a metadata line with the location
+// and date, a blank line, the highlighted text and a "==========" line. The
+// author becomes the quote's author (turning "Last, First" around) and the
+// book title its tag. Notes, bookmarks and empty highlights are skipped.
+//
+// The Kindle keeps the old clipping when a highlight is extended or
+// shortened, so a highlight overlapping an earlier one from the same book,
+// with one text containing the other, replaces it.
+func ReadKindleClippings(r io.Reader) ([]Quote, error) {
+	var clippings []clipping
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), " \t\r")
+		if line != kindleSeparator {
+			lines = append(lines, line)
+			continue
+		}
+
+		if clip, ok := parseClipping(lines); ok {
+			clippings = mergeClipping(clippings, clip)
+		}
+		lines = lines[:0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if clip, ok := parseClipping(lines); ok {
+		clippings = mergeClipping(clippings, clip)
+	}
+
+	quoteList := make([]Quote, len(clippings))
+	for i, clip := range clippings {
+		quoteList[i] = clip.quote
+	}
+	return quoteList, nil
+}
+
+// parseClipping parses the lines of one clippings entry.
+func parseClipping(lines []string) (clipping, bool) {
+	lines = trimBlankLines(lines)
+	if len(lines) < 3 || kindleNote.MatchString(lines[1]) {
+		return clipping{}, false
+	}
+	text := strings.Join(strings.Fields(strings.Join(lines[2:], " ")), " ")
+	if text == "" {
+		return clipping{}, false
+	}
+
+	book, author := splitBookTitle(lines[0])
+	clip := clipping{
+		quote: Quote{Text: text, Author: author, Tags: []string{}},
+		book:  book,
+	}
+	if book != "" {
+		clip.quote.Tags = []string{book}
+	}
+
+	if match := kindleLocation.FindStringSubmatch(lines[1]); match != nil {
+		clip.start, _ = strconv.Atoi(match[1])
+		clip.end = clip.start
+		if match[2] != "" {
+			clip.end, _ = strconv.Atoi(match[2])
+		}
+	}
+	return clip, true
+}
+
+// splitBookTitle splits "Title (Author)" into the title and the author,
+// writing "Last, First" authors as "First Last".
+func splitBookTitle(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	open := strings.LastIndex(line, "(")
+	if open < 0 || !strings.HasSuffix(line, ")") {
+		return line, ""
+	}
+
+	book := strings.TrimSpace(line[:open])
+	author := strings.TrimSpace(line[open+1 : len(line)-1])
+	if last, first, found := strings.Cut(author, ","); found && !strings.ContainsAny(first, ",;&") {
+		author = strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+	}
+	if book == "" {
+		return line, ""
+	}
+	return book, author
+}
+
+// mergeClipping adds clip to clippings, replacing an earlier version of the
+// same highlight.
+func mergeClipping(clippings []clipping, clip clipping) []clipping {
+	for i, earlier := range clippings {
+		if earlier.book != clip.book || earlier.quote.Author != clip.quote.Author {
+			continue
+		}
+		isOverlapping := earlier.start == 0 || clip.start == 0 ||
+			(clip.start <= earlier.end && earlier.start <= clip.end)
+		isContained := strings.Contains(earlier.quote.Text, clip.quote.Text) ||
+			strings.Contains(clip.quote.Text, earlier.quote.Text)
+		if isOverlapping && isContained {
+			clippings[i] = clip
+			return clippings
+		}
+	}
+	return append(clippings, clip)
+}
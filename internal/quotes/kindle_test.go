@@ -0,0 +1,80 @@
+package quotes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// kindleSample is a "My Clippings.txt" with the cases the importer handles.
+const kindleSample = "\ufeffMeditations (Marcus Aurelius)\r\n" +
+	"- Your Highlight on page 12 | Location 120-122 | Added on Monday, March 2, 2020 10:15:22 PM\r\n" +
+	"\r\n" +
+	"You have power over your mind\r\n" +
+	"==========\r\n" +
+	"\ufeffMeditations (Marcus Aurelius)\r\n" +
+	"- Your Note on page 12 | Location 122 | Added on Monday, March 2, 2020 10:16:00 PM\r\n" +
+	"\r\n" +
+	"my own thought\r\n" +
+	"==========\r\n" +
+	"\ufeffMeditations (Marcus Aurelius)\r\n" +
+	"- Your Highlight on page 12 | Location 120-124 | Added on Monday, March 2, 2020 10:17:00 PM\r\n" +
+	"\r\n" +
+	"You have power over your mind - not outside events.\r\n" +
+	"==========\r\n" +
+	"\ufeffThe Pragmatic Programmer (Hunt, Andrew)\r\n" +
+	"- Your Bookmark on page 3 | Location 40 | Added on Tuesday, March 3, 2020 8:00:00 AM\r\n" +
+	"\r\n" +
+	"\r\n" +
+	"==========\r\n" +
+	"\ufeffThe Pragmatic Programmer (Hunt, Andrew)\r\n" +
+	"- Your Highlight at location 200-201 | Added on Tuesday, March 3, 2020 8:05:00 AM\r\n" +
+	"\r\n" +
+	"Care about your\r\n" +
+	"craft.\r\n" +
+	"==========\r\n" +
+	"\ufeffThe Pragmatic Programmer (Hunt, Andrew)\r\n" +
+	"- Your Highlight at location 201-203 | Added on Tuesday, March 3, 2020 8:06:00 AM\r\n" +
+	"\r\n" +
+	"Think! About your work.\r\n" +
+	"==========\r\n"
+
+// TestReadKindleClippings tests parsing highlights and merging edited ones.
+func TestReadKindleClippings(t *testing.T) {
+	got, err := ReadKindleClippings(strings.NewReader(kindleSample))
+	if err != nil {
+		t.Fatalf("ReadKindleClippings() returned an unexpected error: %v", err)
+	}
+
+	expected := []Quote{
+		{Text: "You have power over your mind - not outside events.", Author: "Marcus Aurelius", Tags: []string{"Meditations"}},
+		{Text: "Care about your craft.", Author: "Andrew Hunt", Tags: []string{"The Pragmatic Programmer"}},
+		{Text: "Think! About your work.", Author: "Andrew Hunt", Tags: []string{"The Pragmatic Programmer"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReadKindleClippings() = %#v; want %#v", got, expected)
+	}
+}
+
+// TestSplitBookTitle tests finding the author in a clipping's title line.
+func TestSplitBookTitle(t *testing.T) {
+	tests := []struct {
+		line           string
+		expectedBook   string
+		expectedAuthor string
+	}{
+		{line: "Meditations (Marcus Aurelius)", expectedBook: "Meditations", expectedAuthor: "Marcus Aurelius"},
+		{line: "Dune (Dune Chronicles 1) (Herbert, Frank)", expectedBook: "Dune (Dune Chronicles 1)", expectedAuthor: "Frank Herbert"},
+		{line: "Notes (Smith, A.; Jones, B.)", expectedBook: "Notes", expectedAuthor: "Smith, A.; Jones, B."},
+		{line: "Untitled document", expectedBook: "Untitled document", expectedAuthor: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			book, author := splitBookTitle(tt.line)
+			if book != tt.expectedBook || author != tt.expectedAuthor {
+				t.Errorf("splitBookTitle(%q) = %q, %q; want %q, %q", tt.line, book, author, tt.expectedBook, tt.expectedAuthor)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package quotes
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ====================================================== \\
+//	File Locking
+// ====================================================== \\
+
+// LockTimeout is how long a change to a quotes file waits for another
+// process holding the file's lock before giving up with a *LockedError.
+var LockTimeout = 5 * time.Second
+
+// lockRetryInterval is how often a waiting writer retries the lock.
+const lockRetryInterval = 50 * time.Millisecond
+
+// LockedError is returned when a quotes file stays locked by another process
+// for longer than LockTimeout.
+type LockedError struct {
+	Path string
+	PID  int // 0 when the holder is unknown
+}
+
+func (e *LockedError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("collection %q is locked by another process", e.Path)
+	}
+	return fmt.Sprintf("collection %q is locked by pid %d", e.Path, e.PID)
+}
+
+// Is makes errors.Is(err, ErrLocked) true.
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// lockPath is the lock file guarding filePath. The quotes file itself cannot
+// carry the lock, since every write replaces it with a new file.
+func lockPath(filePath string) string {
+	return filePath + ".lock"
+}
+
+// lockQuotesFile takes the advisory lock of filePath, waiting up to
+// LockTimeout, and records our pid in the lock file so a waiting process can
+// say who holds it. Call the returned function to release the lock.
+//
+// Every read-modify-write of a quotes file holds the lock, so concurrent
+// writers take turns instead of overwriting each other's changes. Plain reads
+// need no lock, since writes are atomic (see writeFileAtomic).
+func lockQuotesFile(filePath string) (func(), error) {
+	lockFile, err := os.OpenFile(lockPath(filePath), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file for %q: %w", filePath, err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		locked, err := tryLockFile(lockFile)
+		if err != nil {
+			lockFile.Close()
+			return nil, fmt.Errorf("failed to lock %q: %w", filePath, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			lockFile.Close()
+			return nil, &LockedError{Path: filePath, PID: lockHolder(filePath)}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	// best effort, the pid is only used for error messages
+	if err := lockFile.Truncate(0); err == nil {
+		lockFile.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+
+	return func() {
+		unlockFile(lockFile)
+		lockFile.Close()
+	}, nil
+}
+
+// lockHolder returns the pid recorded in the lock file of filePath, or 0.
+func lockHolder(filePath string) int {
+	data, err := os.ReadFile(lockPath(filePath))
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
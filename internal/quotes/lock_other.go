@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package quotes
+
+import "os"
+
+// tryLockFile does no locking on systems without flock or LockFileEx;
+// concurrent writers there can still lose each other's changes.
+func tryLockFile(f *os.File) (bool, error) {
+	return true, nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
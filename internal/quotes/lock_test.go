@@ -0,0 +1,121 @@
+package quotes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLockQuotesFile tests that a held lock makes others wait and then fail
+// with the holder's pid.
+func TestLockQuotesFile(t *testing.T) {
+	defer func(old time.Duration) { LockTimeout = old }(LockTimeout)
+	LockTimeout = 100 * time.Millisecond
+
+	testFilePath := filepath.Join(t.TempDir(), "quotes.json")
+
+	unlock, err := lockQuotesFile(testFilePath)
+	if err != nil {
+		t.Fatalf("lockQuotesFile() returned an unexpected error: %v", err)
+	}
+
+	_, err = lockQuotesFile(testFilePath)
+	var lockedErr *LockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("lockQuotesFile() on a locked file error = %v; want *LockedError", err)
+	}
+	if lockedErr.PID != os.Getpid() {
+		t.Errorf("LockedError.PID = %d; want %d", lockedErr.PID, os.Getpid())
+	}
+	expectedMsg := fmt.Sprintf("collection %q is locked by pid %d", testFilePath, os.Getpid())
+	if lockedErr.Error() != expectedMsg {
+		t.Errorf("LockedError.Error() = %q; want %q", lockedErr.Error(), expectedMsg)
+	}
+
+	// a change waiting on the lock gets it once released
+	done := make(chan error)
+	go func() {
+		unlockAgain, err := lockQuotesFile(testFilePath)
+		if err == nil {
+			unlockAgain()
+		}
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	unlock()
+	if err := <-done; err != nil {
+		t.Errorf("lockQuotesFile() after unlock returned an unexpected error: %v", err)
+	}
+}
+
+// TestModifyQuotes_Locked tests that changes fail without writing while
+// another process holds the lock.
+func TestModifyQuotes_Locked(t *testing.T) {
+	defer func(old time.Duration) { LockTimeout = old }(LockTimeout)
+	LockTimeout = 50 * time.Millisecond
+
+	testFilePath := writeTestQuotes(t, testQuotes())
+	unlock, err := lockQuotesFile(testFilePath)
+	if err != nil {
+		t.Fatalf("lockQuotesFile() returned an unexpected error: %v", err)
+	}
+	defer unlock()
+
+	var lockedErr *LockedError
+	if err := AddNewQuote("New", "Someone", nil, testFilePath); !errors.As(err, &lockedErr) {
+		t.Errorf("AddNewQuote() error = %v; want *LockedError", err)
+	}
+	if _, err := RemoveQuote(1, testFilePath); !errors.As(err, &lockedErr) {
+		t.Errorf("RemoveQuote() error = %v; want *LockedError", err)
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	if len(quoteList) != len(testQuotes()) {
+		t.Errorf("file changed while locked, got %d quotes", len(quoteList))
+	}
+}
+
+// TestAddNewQuote_Concurrent tests that concurrent adds are all kept.
+func TestAddNewQuote_Concurrent(t *testing.T) {
+	const writers = 20
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- AddNewQuote(fmt.Sprintf("Quote %d", i), "Writer", nil, testFilePath)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddNewQuote() returned an unexpected error: %v", err)
+		}
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	if expected := len(testQuotes()) + writers; len(quoteList) != expected {
+		t.Errorf("got %d quotes after concurrent adds; want %d", len(quoteList), expected)
+	}
+	seen := make(map[int]bool)
+	for _, quote := range quoteList {
+		if seen[quote.ID] {
+			t.Errorf("id %d used twice", quote.ID)
+		}
+		seen[quote.ID] = true
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package quotes
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset is where the locked byte sits: far past the pid written at the
+// start of the lock file, since Windows locks block reads of the range too.
+const lockOffset = 1 << 30
+
+// tryLockFile takes an exclusive lock on f without blocking. It reports false
+// if another handle holds the lock.
+func tryLockFile(f *os.File) (bool, error) {
+	overlapped := windows.Overlapped{Offset: lockOffset}
+	err := windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlockFile(f *os.File) error {
+	overlapped := windows.Overlapped{Offset: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &overlapped)
+}
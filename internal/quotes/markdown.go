@@ -0,0 +1,297 @@
+package quotes
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ====================================================== \\
+//	Markdown Notes
+// ====================================================== \\
+
+// markdownExtensions are the files ReadMarkdownPath reads from a directory.
+var markdownExtensions = []string{".md", ".markdown", ".txt"}
+
+// markdownAttribution starts the line naming who said a blockquote, e.g.
+// "— Seneca". A plain "-" is not one, since it starts a list item.
+var markdownAttribution = []string{"—", "–", "―", "--", "~"}
+
+// markdownHeading matches an ATX heading, e.g. "## Stoicism ##".
+var markdownHeading = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+// markdownLink matches an inline link, keeping its text.
+var markdownLink = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// ReadMarkdown reads the blockquotes of a Markdown (or plain-text) document
+// as quotes. A blockquote's author is its last line or the first line after
+// it when that starts with a dash ("— Seneca", "-- Seneca"); blockquotes
+// without one are read with no author. Quotes are tagged with the front
+// matter's tags and the headings they are under. Lines of a paragraph are
+// joined, paragraphs are kept apart by line breaks, and fenced code and
+// GitHub callouts ("> [!NOTE]") are skipped.
+func ReadMarkdown(r io.Reader) ([]Quote, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	frontTags, lines := markdownFrontMatter(lines)
+
+	var quoteList []Quote
+	var headings []string // the headings the line is under, outermost first
+	var fence string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if match := markdownHeading.FindStringSubmatch(line); match != nil {
+			level := len(match[1])
+			headings = append(headings[:min(level-1, len(headings))], cleanMarkdown(match[2]))
+			continue
+		}
+
+		if _, ok := cutBlockquote(line); !ok {
+			continue
+		}
+
+		var block []string
+		for ; i < len(lines); i++ {
+			content, ok := cutBlockquote(lines[i])
+			if !ok {
+				break
+			}
+			block = append(block, content)
+		}
+		i-- // the loop moves on to the line after the blockquote
+
+		quote, ok := parseBlockquote(block)
+		if !ok {
+			continue
+		}
+		if quote.Author == "" {
+			// an attribution on the next non-blank line
+			next := i + 1
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next < len(lines) {
+				if author, found := cutMarkdownAttribution(lines[next]); found {
+					quote.Author = author
+					i = next
+				}
+			}
+		}
+
+		quote.Tags = markdownTags(frontTags, headings)
+		quoteList = append(quoteList, quote)
+	}
+
+	return quoteList, nil
+}
+
+// ReadMarkdownPath reads the quotes in the Markdown file at path, or in every
+// .md, .markdown and .txt file below the directory at path (skipping hidden
+// ones), in file name order.
+func ReadMarkdownPath(path string) ([]Quote, error) {
+	var quoteList []Quote
+	err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		isHidden := filePath != path && strings.HasPrefix(entry.Name(), ".")
+		if entry.IsDir() {
+			if isHidden {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filePath != path && (isHidden || !isMarkdownFile(filePath)) {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		found, err := ReadMarkdown(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", filePath, err)
+		}
+		quoteList = append(quoteList, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quoteList, nil
+}
+
+// isMarkdownFile reports whether path has one of markdownExtensions.
+func isMarkdownFile(path string) bool {
+	return slices.Contains(markdownExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// cutBlockquote returns line without its blockquote marker.
+func cutBlockquote(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || !strings.HasPrefix(trimmed, ">") {
+		return "", false
+	}
+	content := strings.TrimPrefix(trimmed, ">")
+	return strings.TrimPrefix(content, " "), true
+}
+
+// parseBlockquote joins the lines of a blockquote into a quote, taking a last
+// line that starts with a dash as the author.
+func parseBlockquote(block []string) (Quote, bool) {
+	block = trimBlankLines(block)
+	if len(block) == 0 || strings.HasPrefix(strings.TrimSpace(block[0]), "[!") {
+		return Quote{}, false
+	}
+
+	var quote Quote
+	if author, found := cutMarkdownAttribution(block[len(block)-1]); found && len(block) > 1 {
+		quote.Author = author
+		block = trimBlankLines(block[:len(block)-1])
+	}
+
+	var paragraphs []string
+	var paragraph []string
+	for _, line := range append(block, "") {
+		if strings.TrimSpace(line) == "" {
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, cleanMarkdown(strings.Join(paragraph, " ")))
+				paragraph = nil
+			}
+			continue
+		}
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+
+	quote.Text = strings.Join(paragraphs, "\n")
+	return quote, quote.Text != ""
+}
+
+// cutMarkdownAttribution returns the author named by an attribution line.
+func cutMarkdownAttribution(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	for _, dash := range markdownAttribution {
+		if author, found := strings.CutPrefix(line, dash); found {
+			author = cleanMarkdown(author)
+			return author, author != ""
+		}
+	}
+	return "", false
+}
+
+// cleanMarkdown strips the emphasis and links around and in text, and the
+// quote marks around a whole quote.
+func cleanMarkdown(text string) string {
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = strings.TrimSpace(text)
+	for _, marker := range []string{"**", "__", "*", "_", "\"", "“"} {
+		closing := marker
+		if marker == "“" {
+			closing = "”"
+		}
+		if len(text) > len(marker)+len(closing) && strings.HasPrefix(text, marker) && strings.HasSuffix(text, closing) {
+			text = strings.TrimSpace(text[len(marker) : len(text)-len(closing)])
+		}
+	}
+	return text
+}
+
+// markdownTags combines the front matter tags with the headings above a
+// quote, dropping blanks and repeats.
+func markdownTags(frontTags []string, headings []string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range append(append([]string{}, frontTags...), headings...) {
+		key := normalize(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// markdownFrontMatter reads the tags from a YAML front matter block at the
+// start of lines ("tags: [a, b]", "tags: a, b" or a "- a" list below
+// "tags:") and returns them with the lines after the block.
+func markdownFrontMatter(lines []string) ([]string, []string) {
+	if len(lines) == 0 || strings.TrimPrefix(lines[0], "\ufeff") != "---" {
+		return nil, lines
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" || lines[i] == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, lines
+	}
+
+	var tags []string
+	isInTags := false
+	for _, line := range lines[1:end] {
+		if isInTags {
+			if item, found := strings.CutPrefix(strings.TrimSpace(line), "- "); found {
+				tags = append(tags, cleanTag(item))
+				continue
+			}
+			isInTags = false
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !found || (key != "tags" && key != "tag") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), "[]")
+		if value == "" {
+			isInTags = true
+			continue
+		}
+		for _, tag := range strings.Split(value, ",") {
+			tags = append(tags, cleanTag(tag))
+		}
+	}
+
+	return tags, lines[end+1:]
+}
+
+// cleanTag strips the quotes and "#" a front matter tag may be written with.
+func cleanTag(tag string) string {
+	return strings.TrimPrefix(strings.Trim(strings.TrimSpace(tag), `"'`), "#")
+}
@@ -0,0 +1,123 @@
+package quotes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// markdownSample is a reading note with the cases the importer handles.
+const markdownSample = `---
+title: Reading notes
+tags: [stoicism, "#reading"]
+---
+# Letters from a Stoic
+
+> We suffer more often in imagination
+> than in reality.
+> — *Seneca*
+
+## On time
+
+> "Hold every hour in your grasp."
+
+-- [Seneca](https://en.wikipedia.org/wiki/Seneca_the_Younger)
+
+> [!NOTE]
+> This is a callout, not a quote.
+
+` + "```" + `
+> not a quote, inside code
+` + "```" + `
+
+> First paragraph.
+>
+> Second paragraph.
+
+- a list item, not an author
+`
+
+// TestReadMarkdown tests extracting blockquotes, attributions and tags.
+func TestReadMarkdown(t *testing.T) {
+	got, err := ReadMarkdown(strings.NewReader(markdownSample))
+	if err != nil {
+		t.Fatalf("ReadMarkdown() returned an unexpected error: %v", err)
+	}
+
+	expected := []Quote{
+		{Text: "We suffer more often in imagination than in reality.", Author: "Seneca", Tags: []string{"stoicism", "reading", "Letters from a Stoic"}},
+		{Text: "Hold every hour in your grasp.", Author: "Seneca", Tags: []string{"stoicism", "reading", "Letters from a Stoic", "On time"}},
+		{Text: "First paragraph.\nSecond paragraph.", Tags: []string{"stoicism", "reading", "Letters from a Stoic", "On time"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReadMarkdown() = %#v; want %#v", got, expected)
+	}
+}
+
+// TestMarkdownFrontMatter tests the ways front matter lists tags.
+func TestMarkdownFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{name: "Inline list", content: "---\ntags: [a, b]\n---\n", expected: []string{"a", "b"}},
+		{name: "Comma separated", content: "---\ntags: a, b\n---\n", expected: []string{"a", "b"}},
+		{name: "Block list", content: "---\ntags:\n  - a\n  - 'b'\nauthor: x\n---\n", expected: []string{"a", "b"}},
+		{name: "No tags", content: "---\ntitle: x\n---\n", expected: nil},
+		{name: "Not closed", content: "---\ntags: a\n", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, _ := markdownFrontMatter(strings.Split(tt.content, "\n"))
+			if !reflect.DeepEqual(tags, tt.expected) {
+				t.Errorf("markdownFrontMatter() tags = %q; want %q", tags, tt.expected)
+			}
+		})
+	}
+}
+
+// TestReadMarkdownPath tests reading every note below a directory.
+func TestReadMarkdownPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.md":             "> From a\n— A\n",
+		"sub/b.markdown":   "> From b\n",
+		"sub/c.txt":        "> From c\n",
+		"sub/d.json":       "> not markdown\n",
+		".hidden/e.md":     "> hidden\n",
+		"sub/.draft.md":    "> hidden too\n",
+		"sub/deeper/f.MD":  "> From f\n",
+		"sub/deeper/g.txt": "no quotes here\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	quoteList, err := ReadMarkdownPath(dir)
+	if err != nil {
+		t.Fatalf("ReadMarkdownPath() returned an unexpected error: %v", err)
+	}
+	var got []string
+	for _, quote := range quoteList {
+		got = append(got, quote.Text)
+	}
+	if expected := []string{"From a", "From b", "From c", "From f"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReadMarkdownPath() texts = %v; want %v", got, expected)
+	}
+
+	// a single file is read whatever its extension
+	quoteList, err = ReadMarkdownPath(filepath.Join(dir, "sub/d.json"))
+	if err != nil || len(quoteList) != 1 {
+		t.Errorf("ReadMarkdownPath() of a file = %v, %v; want its one quote", quoteList, err)
+	}
+}
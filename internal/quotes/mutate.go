@@ -0,0 +1,149 @@
+package quotes
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ====================================================== \\
+//	Quote Mutations
+// ====================================================== \\
+
+// modifyQuotes loads the quotes at filePath, hands them to change and writes
+// the result back, or with UseJournal journals the difference, holding the
+// file's lock throughout (see lockQuotesFile). Nothing is written, backed up
+// or journaled if change returns an error or leaves the quotes as they were,
+// so a change that does nothing does not use up an undo.
+func modifyQuotes(filePath string, change func([]Quote) ([]Quote, error)) error {
+	unlock, err := lockQuotesFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	quoteList, err := loadCollection(filePath)
+	if err != nil {
+		return err
+	}
+	// a deep copy, since change may reuse quoteList and edit tags in place
+	before := make([]Quote, len(quoteList))
+	for i, quote := range quoteList {
+		quote.Tags = slices.Clone(quote.Tags)
+		before[i] = quote
+	}
+
+	quoteList, err = change(quoteList)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(quoteList, before) {
+		return nil
+	}
+
+	// remember the IDs of quotes the change removed, so they are not reused
+	if err := recordNextID(NextID(before), filePath); err != nil {
+		return &WriteError{Path: filePath, Err: err}
+	}
+
+	if UseJournal {
+		assignIDsFrom(recordedNextID(filePath), quoteList)
+		return journalQuotes(before, quoteList, filePath)
+	}
+	return WriteQuoteToFile(quoteList, filePath)
+}
+
+// RemoveQuote deletes the quote with the given ID from the file at filePath
+// and returns it. A missing ID is a *NotFoundError.
+func RemoveQuote(id int, filePath string) (Quote, error) {
+	removed, err := RemoveQuotes([]int{id}, filePath)
+	if err != nil {
+		return Quote{}, err
+	}
+	return removed[0], nil
+}
+
+// RemoveQuotes deletes every quote whose ID is in ids from the file at
+// filePath and returns the removed quotes in file order. If any ID is missing
+// nothing is removed. Removing every quote leaves an empty collection, which
+// reads report as ErrEmptyCollection until a quote is added again.
+func RemoveQuotes(ids []int, filePath string) ([]Quote, error) {
+	var removed []Quote
+
+	err := modifyQuotes(filePath, func(quoteList []Quote) ([]Quote, error) {
+		targets := make(map[int]bool, len(ids))
+		for _, id := range ids {
+			if IndexOfID(quoteList, id) < 0 {
+				return nil, &NotFoundError{ID: id, Path: filePath}
+			}
+			targets[id] = true
+		}
+
+		kept := quoteList[:0]
+		for _, quote := range quoteList {
+			if targets[quote.ID] {
+				removed = append(removed, quote)
+			} else {
+				kept = append(kept, quote)
+			}
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// UpdateQuote replaces the quote that has updated.ID with updated, keeping its
+// position in the file.
+func UpdateQuote(updated Quote, filePath string) error {
+	return modifyQuotes(filePath, func(quoteList []Quote) ([]Quote, error) {
+		index := IndexOfID(quoteList, updated.ID)
+		if index < 0 {
+			return nil, &NotFoundError{ID: updated.ID, Path: filePath}
+		}
+
+		quoteList[index] = updated
+		return quoteList, nil
+	})
+}
+
+// EditQuote applies edit to the quote with the given ID as it is in the file
+// at filePath, while holding the file's lock, so the fields edit leaves alone
+// keep any change made since the caller loaded the quote. The quote keeps its
+// ID and position. A missing ID is a *NotFoundError.
+func EditQuote(id int, edit func(*Quote), filePath string) error {
+	return modifyQuotes(filePath, func(quoteList []Quote) ([]Quote, error) {
+		index := IndexOfID(quoteList, id)
+		if index < 0 {
+			return nil, &NotFoundError{ID: id, Path: filePath}
+		}
+
+		edit(&quoteList[index])
+		quoteList[index].ID = id
+		return quoteList, nil
+	})
+}
+
+// ReplaceTags sets the tags of the quote with the given ID, dropping blank
+// tags. A nil or empty tags clears them.
+func ReplaceTags(id int, tags []string, filePath string) error {
+	return modifyQuotes(filePath, func(quoteList []Quote) ([]Quote, error) {
+		index := IndexOfID(quoteList, id)
+		if index < 0 {
+			return nil, &NotFoundError{ID: id, Path: filePath}
+		}
+
+		cleaned := []string{}
+		for _, tag := range tags {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				cleaned = append(cleaned, tag)
+			}
+		}
+
+		quoteList[index].Tags = cleaned
+		return quoteList, nil
+	})
+}
@@ -0,0 +1,225 @@
+package quotes
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestQuotes writes quoteList to a fresh file in a temp dir and returns its path.
+func writeTestQuotes(t *testing.T, quoteList []Quote) string {
+	t.Helper()
+	testFilePath := filepath.Join(t.TempDir(), "quotes.json")
+	if err := WriteQuoteToFile(quoteList, testFilePath); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	return testFilePath
+}
+
+// testQuotes returns a fresh copy of the quotes the file tests start from.
+func testQuotes() []Quote {
+	return []Quote{
+		{ID: 1, Text: "Great work", Author: "Steve Jobs", Tags: []string{"work"}},
+		{ID: 2, Text: "Be yourself", Author: "Oscar Wilde", Tags: []string{"humor"}},
+		{ID: 3, Text: "To be", Author: "William Shakespeare", Tags: []string{"drama"}},
+	}
+}
+
+//				Test - RemoveQuote(s)
+// ====================================================== \\
+
+// TestRemoveQuotes tests removing one or more quotes by ID.
+func TestRemoveQuotes(t *testing.T) {
+	tests := []struct {
+		name            string
+		ids             []int
+		expectedRemoved []int
+		expectedKept    []int
+		expectErr       bool
+	}{
+		{name: "Remove one", ids: []int{2}, expectedRemoved: []int{2}, expectedKept: []int{1, 3}},
+		{name: "Remove several", ids: []int{3, 1}, expectedRemoved: []int{1, 3}, expectedKept: []int{2}},
+		{name: "Missing id removes nothing", ids: []int{1, 9}, expectedKept: []int{1, 2, 3}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFilePath := writeTestQuotes(t, testQuotes())
+
+			removed, err := RemoveQuotes(tt.ids, testFilePath)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("RemoveQuotes() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if ids := quoteIDs(removed); !reflect.DeepEqual(ids, tt.expectedRemoved) {
+				t.Errorf("RemoveQuotes() removed %v, want %v", ids, tt.expectedRemoved)
+			}
+
+			quoteList, err := LoadQuotesFromFile(testFilePath)
+			if err != nil {
+				t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+			}
+			if ids := quoteIDs(quoteList); !reflect.DeepEqual(ids, tt.expectedKept) {
+				t.Errorf("RemoveQuotes() kept %v, want %v", ids, tt.expectedKept)
+			}
+		})
+	}
+}
+
+// TestRemoveQuote tests that the removed quote is returned.
+func TestRemoveQuote(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	removed, err := RemoveQuote(2, testFilePath)
+	if err != nil {
+		t.Fatalf("RemoveQuote returned an unexpected error: %v", err)
+	}
+	if removed.Author != "Oscar Wilde" {
+		t.Errorf("RemoveQuote() returned %+v, want the Oscar Wilde quote", removed)
+	}
+}
+
+// TestRemoveQuotes_Last tests that a collection emptied by removing every
+// quote only stops reads: quotes can still be added and imported to it, with
+// or without the journal.
+func TestRemoveQuotes_Last(t *testing.T) {
+	for _, isJournaled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("journal %v", isJournaled), func(t *testing.T) {
+			if isJournaled {
+				useJournal(t)
+			}
+			testFilePath := writeTestQuotes(t, testQuotes())
+
+			if _, err := RemoveQuotes([]int{1, 2, 3}, testFilePath); err != nil {
+				t.Fatalf("RemoveQuotes returned an unexpected error: %v", err)
+			}
+			if _, err := LoadQuotesFromFile(testFilePath); !errors.Is(err, ErrEmptyCollection) {
+				t.Errorf("LoadQuotesFromFile() error = %v, want ErrEmptyCollection", err)
+			}
+
+			if err := AddNewQuote("Fresh start", "Anon", nil, testFilePath); err != nil {
+				t.Fatalf("AddNewQuote returned an unexpected error: %v", err)
+			}
+			if _, err := ImportQuotes([]Quote{{Text: "Imported"}}, false, testFilePath); err != nil {
+				t.Fatalf("ImportQuotes returned an unexpected error: %v", err)
+			}
+
+			quoteList, err := LoadQuotesFromFile(testFilePath)
+			if err != nil {
+				t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+			}
+			if len(quoteList) != 2 {
+				t.Errorf("LoadQuotesFromFile() = %+v, want the added and the imported quote", quoteList)
+			}
+		})
+	}
+}
+
+//				Test - UpdateQuote / EditQuote / ReplaceTags
+// ====================================================== \\
+
+// TestUpdateQuote tests that the quote is replaced in place.
+func TestUpdateQuote(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	updated := Quote{ID: 2, Text: "Be yourself!", Author: "O. Wilde", Tags: []string{"identity"}}
+	if err := UpdateQuote(updated, testFilePath); err != nil {
+		t.Fatalf("UpdateQuote returned an unexpected error: %v", err)
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(quoteList[1], updated) {
+		t.Errorf("UpdateQuote() \ngot  = %+v, \nwant = %+v", quoteList[1], updated)
+	}
+
+	if err := UpdateQuote(Quote{ID: 42, Text: "nope"}, testFilePath); err == nil {
+		t.Error("UpdateQuote expected an error for a missing id, but got none.")
+	}
+}
+
+// TestEditQuote tests that an edit only touches the fields it sets, keeping a
+// change made to the others after the quote was loaded.
+func TestEditQuote(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	// another process changes the text while this one edits the author
+	if err := UpdateQuote(Quote{ID: 2, Text: "Be yourself!", Author: "Oscar Wilde", Tags: []string{"humor"}}, testFilePath); err != nil {
+		t.Fatalf("UpdateQuote returned an unexpected error: %v", err)
+	}
+	err := EditQuote(2, func(quote *Quote) {
+		quote.Author = "O. Wilde"
+		quote.ID = 7
+	}, testFilePath)
+	if err != nil {
+		t.Fatalf("EditQuote returned an unexpected error: %v", err)
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	expected := Quote{ID: 2, Text: "Be yourself!", Author: "O. Wilde", Tags: []string{"humor"}}
+	if !reflect.DeepEqual(quoteList[1], expected) {
+		t.Errorf("EditQuote() \ngot  = %+v, \nwant = %+v", quoteList[1], expected)
+	}
+
+	if err := EditQuote(42, func(*Quote) {}, testFilePath); !errors.Is(err, ErrNotFound) {
+		t.Errorf("EditQuote() error = %v for a missing id, want ErrNotFound", err)
+	}
+}
+
+// TestEditQuote_TagsInPlace tests that editing a tag in place is saved, with
+// or without the journal.
+func TestEditQuote_TagsInPlace(t *testing.T) {
+	for _, isJournaled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("journal %v", isJournaled), func(t *testing.T) {
+			if isJournaled {
+				useJournal(t)
+			}
+			testFilePath := writeTestQuotes(t, testQuotes())
+
+			if err := EditQuote(1, func(quote *Quote) { quote.Tags[0] = "craft" }, testFilePath); err != nil {
+				t.Fatalf("EditQuote returned an unexpected error: %v", err)
+			}
+
+			quoteList, err := LoadQuotesFromFile(testFilePath)
+			if err != nil {
+				t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+			}
+			if expectedTags := []string{"craft"}; !reflect.DeepEqual(quoteList[0].Tags, expectedTags) {
+				t.Errorf("EditQuote() of a tag in place saved %v, want %v", quoteList[0].Tags, expectedTags)
+			}
+		})
+	}
+}
+
+// TestReplaceTags tests that tags are replaced and blank tags dropped.
+func TestReplaceTags(t *testing.T) {
+	testFilePath := writeTestQuotes(t, testQuotes())
+
+	if err := ReplaceTags(1, []string{"focus", " ", " craft "}, testFilePath); err != nil {
+		t.Fatalf("ReplaceTags returned an unexpected error: %v", err)
+	}
+
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	expectedTags := []string{"focus", "craft"}
+	if !reflect.DeepEqual(quoteList[0].Tags, expectedTags) {
+		t.Errorf("ReplaceTags() \ngot  = %v, \nwant = %v", quoteList[0].Tags, expectedTags)
+	}
+}
+
+// quoteIDs returns the IDs of quoteList in order.
+func quoteIDs(quoteList []Quote) []int {
+	var ids []int
+	for _, quote := range quoteList {
+		ids = append(ids, quote.ID)
+	}
+	return ids
+}
@@ -0,0 +1,66 @@
+package quotes
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ====================================================== \\
+//	Unicode Normalization
+// ====================================================== \\
+
+// StripAccents makes every search ignore accents and other diacritics, so
+// "Camus" finds "Camús" and "Nietzsche" matches however its letters were
+// composed. Set it before building an Index; the index and the searches run
+// on it have to agree.
+var StripAccents = true
+
+// foldedLetters are letters that do not decompose into a base letter plus an
+// accent, but that people type as plain ASCII.
+var foldedLetters = strings.NewReplacer(
+	"ø", "o", "ł", "l", "đ", "d", "ħ", "h", "ı", "i", "æ", "ae", "œ", "oe", "þ", "th",
+)
+
+// normalize folds s so that text which reads the same compares equal: it is
+// NFKC normalized (ligatures, full-width and composed/decomposed forms are
+// unified), case folded ("Straße" and "STRASSE" both become "strasse") and,
+// when StripAccents is set, stripped of diacritics. Every search compares
+// normalized values on both sides.
+func normalize(s string) string {
+	if isASCII(s) {
+		return strings.ToLower(s)
+	}
+
+	s = cases.Fold().String(norm.NFKC.String(s))
+	if StripAccents {
+		s = removeAccents(s)
+	}
+	return s
+}
+
+// removeAccents decomposes s, drops the combining marks and recomposes what
+// is left.
+func removeAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return foldedLetters.Replace(stripped)
+}
+
+// isASCII reports whether s needs no Unicode handling beyond lowercasing.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package quotes
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNormalize tests Unicode normalization, case folding and accent removal.
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		stripAccents bool
+		expected     string
+	}{
+		{name: "ASCII is lowercased", input: "Albert CAMUS", stripAccents: true, expected: "albert camus"},
+		{name: "Accents are removed", input: "Albert Camús", stripAccents: true, expected: "albert camus"},
+		{name: "Decomposed accents are removed", input: "Camu\u0301s", stripAccents: true, expected: "camus"},
+		{name: "Sharp s folds to ss", input: "Straße", stripAccents: true, expected: "strasse"},
+		{name: "Ligatures are expanded", input: "ﬁnal ﬂight", stripAccents: true, expected: "final flight"},
+		{name: "Letters without a base letter", input: "Søren Kierkegaard", stripAccents: true, expected: "soren kierkegaard"},
+		{name: "Full-width letters", input: "ＡＢＣ", stripAccents: true, expected: "abc"},
+		{name: "Accents kept when not stripping", input: "Albert Camús", stripAccents: false, expected: "albert camús"},
+		{name: "Composed and decomposed agree when not stripping", input: "Camu\u0301s", stripAccents: false, expected: "camús"},
+	}
+
+	defer func(old bool) { StripAccents = old }(StripAccents)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			StripAccents = tt.stripAccents
+			if got := normalize(tt.input); got != tt.expected {
+				t.Errorf("normalize(%q) = %q; want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSearch_Normalized tests that searches ignore accents and Unicode forms
+// on both the quote and the query side, with and without the index.
+func TestSearch_Normalized(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "One must imagine Sisyphus happy.", Author: "Albert Camús", Tags: []string{"absurdisme"}},
+		{ID: 2, Text: "He who has a why to live can bear almost any how.", Author: "Friedrich Nietzsche", Tags: []string{"Philosophie"}},
+		{ID: 3, Text: "Die Straße ist lang.", Author: "Émile Zola", Tags: []string{"Société"}},
+		{ID: 4, Text: "Life is what happens.", Author: "John Lennon", Tags: []string{"life"}},
+	}
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		expectedIDs []int
+	}{
+		{name: "Author typed without accent", filter: AuthorFilter{Author: "Camus"}, expectedIDs: []int{1}},
+		{name: "Author typed with accent", filter: AuthorFilter{Author: "albert camús", Exact: true}, expectedIDs: []int{1}},
+		{name: "Author with decomposed accent", filter: AuthorFilter{Author: "E\u0301mile Zola", Exact: true}, expectedIDs: []int{3}},
+		{name: "Author in upper case", filter: AuthorFilter{Author: "NIETZSCHE"}, expectedIDs: []int{2}},
+		{name: "Tag typed without accent", filter: TagFilter{Tag: "societe", Exact: true}, expectedIDs: []int{3}},
+		{name: "Text sharp s", filter: TextFilter{Text: "strasse", Exact: true}, expectedIDs: []int{3}},
+		{name: "Text typed with sharp s", filter: TextFilter{Text: "STRAẞE", Exact: true}, expectedIDs: []int{3}},
+		{name: "Phrase with accents", filter: TextFilter{Text: "dïe straße", Mode: Phrase}, expectedIDs: []int{3}},
+	}
+
+	idx := NewIndex(sampleQuotes)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteIDs(Search(sampleQuotes, tt.filter)); !reflect.DeepEqual(got, tt.expectedIDs) {
+				t.Errorf("Search() IDs = %v; want %v", got, tt.expectedIDs)
+			}
+			if got := quoteIDs(idx.Search(tt.filter)); !reflect.DeepEqual(got, tt.expectedIDs) {
+				t.Errorf("Index.Search() IDs = %v; want %v", got, tt.expectedIDs)
+			}
+		})
+	}
+
+	t.Run("SearchByQuoteAuthor", func(t *testing.T) {
+		if got := quoteIDs(SearchByQuoteAuthor(sampleQuotes, "emile", false)); !reflect.DeepEqual(got, []int{3}) {
+			t.Errorf("SearchByQuoteAuthor() IDs = %v; want [3]", got)
+		}
+		if got := quoteIDs(SearchByPartialQuoteAuthor(sampleQuotes, "camus")); !reflect.DeepEqual(got, []int{1}) {
+			t.Errorf("SearchByPartialQuoteAuthor() IDs = %v; want [1]", got)
+		}
+	})
+
+	t.Run("Keep accents", func(t *testing.T) {
+		defer func(old bool) { StripAccents = old }(StripAccents)
+		StripAccents = false
+
+		idx := NewIndex(sampleQuotes)
+		if got := idx.SearchByQuoteAuthor("Camus", false); got != nil {
+			t.Errorf("Index.SearchByQuoteAuthor(%q) = %v; want nil", "Camus", got)
+		}
+		if got := quoteIDs(idx.SearchByQuoteAuthor("Camu\u0301s", false)); !reflect.DeepEqual(got, []int{1}) {
+			t.Errorf("Index.SearchByQuoteAuthor(%q) IDs = %v; want [1]", "Camu\u0301s", got)
+		}
+	})
+}
+
+// TestCountAuthors_Normalized tests that accented and unaccented spellings
+// are counted together under the first one seen.
+func TestCountAuthors_Normalized(t *testing.T) {
+	sampleQuotes := []Quote{
+		{Author: "Albert Camús", Tags: []string{"Société"}},
+		{Author: "Albert Camus", Tags: []string{"societe"}},
+	}
+
+	if got, want := CountAuthors(sampleQuotes), map[string]int{"Albert Camús": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CountAuthors() = %v; want %v", got, want)
+	}
+	if got, want := CountTags(sampleQuotes), map[string]int{"société": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CountTags() = %v; want %v", got, want)
+	}
+}
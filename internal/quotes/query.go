@@ -0,0 +1,406 @@
+package quotes
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ====================================================== \\
+//	Query Language
+// ====================================================== \\
+
+// ParseQuery parses a boolean search query into a Filter tree, e.g.
+//
+//	tag:stoicism AND NOT author:seneca OR text:"fortune favors"
+//
+// Grammar:
+//
+//	query   = or
+//	or      = and { "OR" and }
+//	and     = unary { ["AND"] unary }     (words next to each other are ANDed)
+//	unary   = "NOT" unary | "(" or ")" | term
+//	term    = [field ":"] value
+//	field   = "text" | "author" | "tag" | "tags" | "any"
+//	value   = word | "quoted phrase" | /regular expression/
+//
+// AND, OR and NOT must be upper case; NOT binds tightest, then AND, then OR.
+// A value without a field searches the quote text, and so does a word before a
+// colon that is not a field name ("Remember: you will die"). Words are matched
+// the way SearchByQuoteTag, SearchByQuoteAuthor and SearchByText match them: as
+// sub-strings, as whole values/words when isExact is set, and tags and authors
+// typo tolerantly when isFuzzy is set. A quoted text value must appear as a
+// phrase; a /value/ is a RegexFilter.
+//
+// Errors are *QueryError values carrying the column of the problem.
+func ParseQuery(input string, isExact bool, isFuzzy bool) (Filter, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{input: input, tokens: tokens, isExact: isExact, isFuzzy: isFuzzy}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorAt(p.peek(), "empty query")
+	}
+
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		if tok.kind == tokenRParen {
+			return nil, p.errorAt(tok, "unexpected ) without matching (")
+		}
+		return nil, p.errorAt(tok, "unexpected %s", tok)
+	}
+
+	return filter, nil
+}
+
+// QueryError is a query syntax error. Column is the 1-based rune position of
+// the problem in Query.
+type QueryError struct {
+	Query  string
+	Column int
+	Msg    string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s", e.Column, e.Msg)
+}
+
+// Pointer returns the query with a caret under the offending column:
+//
+//	tag:stoic AND (author:seneca
+//	                            ^
+func (e *QueryError) Pointer() string {
+	return e.Query + "\n" + strings.Repeat(" ", e.Column-1) + "^"
+}
+
+// QueryWords returns the words a search for filter is looking for, from the
+// tag, author and text filters that are not under a NotFilter. They are the
+// words results should be ranked by (see Index.Rank).
+func QueryWords(filter Filter) []string {
+	var words []string
+
+	switch f := filter.(type) {
+	case TagFilter:
+		words = append(words, f.Tag)
+	case AuthorFilter:
+		words = append(words, f.Author)
+	case TextFilter:
+		words = append(words, f.Text)
+	case AndFilter:
+		for _, sub := range f {
+			words = append(words, QueryWords(sub)...)
+		}
+	case OrFilter:
+		for _, sub := range f {
+			words = append(words, QueryWords(sub)...)
+		}
+	}
+
+	return words
+}
+
+// ====================================================== \\
+//	Lexer
+// ====================================================== \\
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+// queryToken is one lexed token. For terms, field is the lowercased field
+// prefix ("" if none) and value the unquoted value.
+type queryToken struct {
+	kind   tokenKind
+	column int
+	field  string
+	value  string
+	quoted bool
+	regex  bool
+}
+
+func (t queryToken) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenLParen:
+		return "("
+	case tokenRParen:
+		return ")"
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// lexQuery splits input into tokens, ending with a tokenEOF.
+func lexQuery(input string) ([]queryToken, error) {
+	runes := []rune(input)
+	var tokens []queryToken
+
+	errorAt := func(pos int, format string, args ...any) error {
+		return &QueryError{Query: input, Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	isWordRune := func(r rune) bool {
+		return !unicode.IsSpace(r) && r != '(' && r != ')' && r != '"'
+	}
+
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+			continue
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, column: pos + 1})
+			pos++
+			continue
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, column: pos + 1})
+			pos++
+			continue
+		}
+
+		tok := queryToken{kind: tokenTerm, column: pos + 1}
+
+		// optional field prefix: a field name followed by ':'; other words
+		// ending in ':' are plain text
+		end := pos
+		for end < len(runes) && unicode.IsLetter(runes[end]) {
+			end++
+		}
+		if end > pos && end < len(runes) && runes[end] == ':' {
+			field := strings.ToLower(string(runes[pos:end]))
+			if _, err := ParseField(field); err == nil {
+				tok.field = field
+				pos = end + 1
+			}
+		}
+
+		switch {
+		case pos < len(runes) && (runes[pos] == '"' || runes[pos] == '/'):
+			delim := runes[pos]
+			start := pos
+			var value strings.Builder
+			pos++
+			for ; pos < len(runes) && runes[pos] != delim; pos++ {
+				if runes[pos] == '\\' && pos+1 < len(runes) && runes[pos+1] == delim {
+					pos++
+				} else if runes[pos] == '\\' && delim == '/' && pos+1 < len(runes) {
+					// keep other escapes for the regexp
+					value.WriteRune(runes[pos])
+					pos++
+				}
+				value.WriteRune(runes[pos])
+			}
+			if pos >= len(runes) {
+				if delim == '"' {
+					return nil, errorAt(start, "unterminated quoted phrase")
+				}
+				return nil, errorAt(start, "unterminated regular expression")
+			}
+			pos++ // closing delimiter
+
+			tok.value = value.String()
+			tok.quoted = delim == '"'
+			tok.regex = delim == '/'
+
+		default:
+			start := pos
+			for pos < len(runes) && isWordRune(runes[pos]) {
+				pos++
+			}
+			if pos == start {
+				if tok.field != "" {
+					return nil, errorAt(start, "missing value after %s:", tok.field)
+				}
+				return nil, errorAt(start, "unexpected %q", string(runes[pos]))
+			}
+			tok.value = string(runes[start:pos])
+
+			if tok.field == "" {
+				switch tok.value {
+				case "AND":
+					tok.kind = tokenAnd
+				case "OR":
+					tok.kind = tokenOr
+				case "NOT":
+					tok.kind = tokenNot
+				}
+			}
+		}
+
+		tokens = append(tokens, tok)
+	}
+
+	return append(tokens, queryToken{kind: tokenEOF, column: len(runes) + 1}), nil
+}
+
+// ====================================================== \\
+//	Parser
+// ====================================================== \\
+
+type queryParser struct {
+	input   string
+	tokens  []queryToken
+	pos     int
+	isExact bool
+	isFuzzy bool
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) errorAt(tok queryToken, format string, args ...any) error {
+	return &QueryError{Query: p.input, Column: tok.column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *queryParser) parseOr() (Filter, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	filters := OrFilter{first}
+	for p.peek().kind == tokenOr {
+		p.next()
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, next)
+	}
+
+	if len(filters) == 1 {
+		return first, nil
+	}
+	return filters, nil
+}
+
+func (p *queryParser) parseAnd() (Filter, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	filters := AndFilter{first}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenTerm, tokenNot, tokenLParen:
+			// implicit AND between neighbours
+		default:
+			if len(filters) == 1 {
+				return first, nil
+			}
+			return filters, nil
+		}
+
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, next)
+	}
+}
+
+func (p *queryParser) parseUnary() (Filter, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNot:
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return NotFilter{Filter: inner}, nil
+
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, "expected ) to close ( at column %d, found %s", tok.column, closing)
+		}
+		return inner, nil
+
+	case tokenTerm:
+		return p.termFilter(tok)
+
+	case tokenEOF:
+		return nil, p.errorAt(tok, "query ends where a search term was expected")
+	}
+
+	return nil, p.errorAt(tok, "expected a search term, found %s", tok)
+}
+
+// termFilter builds the filter for one field:value term.
+func (p *queryParser) termFilter(tok queryToken) (Filter, error) {
+	field := FieldText
+	if tok.field != "" {
+		field, _ = ParseField(tok.field) // checked by the lexer
+	}
+
+	if tok.regex {
+		filter, err := NewRegexFilter(field, tok.value)
+		if err != nil {
+			return nil, p.errorAt(tok, "%v", err)
+		}
+		return filter, nil
+	}
+
+	if strings.TrimSpace(tok.value) == "" {
+		return nil, p.errorAt(tok, "empty search term")
+	}
+
+	textMode := AllTerms
+	if tok.quoted {
+		textMode = Phrase
+	}
+
+	switch field {
+	case FieldAuthor:
+		return AuthorFilter{Author: tok.value, Exact: p.isExact, Fuzzy: p.isFuzzy}, nil
+	case FieldTag:
+		return TagFilter{Tag: tok.value, Exact: p.isExact, Fuzzy: p.isFuzzy}, nil
+	case FieldAny:
+		return OrFilter{
+			TextFilter{Text: tok.value, Exact: p.isExact, Mode: textMode},
+			AuthorFilter{Author: tok.value, Exact: p.isExact, Fuzzy: p.isFuzzy},
+			TagFilter{Tag: tok.value, Exact: p.isExact, Fuzzy: p.isFuzzy},
+		}, nil
+	}
+
+	if len(tokenize(tok.value)) == 0 {
+		return nil, p.errorAt(tok, "%q has no words to search for", tok.value)
+	}
+	return TextFilter{Text: tok.value, Exact: p.isExact, Mode: textMode}, nil
+}
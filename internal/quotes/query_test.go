@@ -0,0 +1,124 @@
+package quotes
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+//				Test - ParseQuery
+// ====================================================== \\
+
+// TestParseQuery tests that parsed queries select the right quotes.
+func TestParseQuery(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "We suffer more often in imagination than in reality.", Author: "Seneca", Tags: []string{"stoicism", "fear"}},
+		{ID: 2, Text: "You have power over your mind - not outside events.", Author: "Marcus Aurelius", Tags: []string{"stoicism", "mind"}},
+		{ID: 3, Text: "Fortune favors the bold.", Author: "Virgil", Tags: []string{"courage"}},
+		{ID: 4, Text: "Luck is what happens when preparation meets opportunity; fortune never favors the lazy.", Author: "Seneca", Tags: []string{"luck"}},
+		{ID: 5, Text: "The happiness of your life depends upon the quality of your thoughts.", Author: "Marcus Aurelius", Tags: []string{"happiness", "mind"}},
+	}
+	idx := NewIndex(sampleQuotes)
+
+	tests := []struct {
+		name        string
+		query       string
+		isExact     bool
+		isFuzzy     bool
+		expectedIDs []int
+	}{
+		{name: "Single field", query: "tag:stoicism", expectedIDs: []int{1, 2}},
+		{name: "Bare words search text", query: "fortune favors", expectedIDs: []int{3, 4}},
+		{name: "Quoted phrase", query: `text:"fortune favors"`, expectedIDs: []int{3}},
+		{name: "Bare quoted phrase", query: `"your mind"`, expectedIDs: []int{2}},
+		{name: "AND NOT OR", query: `tag:stoicism AND NOT author:seneca OR text:"fortune favors"`, expectedIDs: []int{2, 3}},
+		{name: "Implicit AND", query: "author:marcus tag:mind happiness", expectedIDs: []int{5}},
+		{name: "Parentheses", query: "author:seneca AND (tag:luck OR tag:courage)", expectedIDs: []int{4}},
+		{name: "NOT binds tighter than AND", query: "NOT tag:stoicism author:marcus", expectedIDs: []int{5}},
+		{name: "Lower case operators are words", query: "in and", expectedIDs: nil},
+		{name: "Any field", query: "any:virgil OR any:luck", expectedIDs: []int{3, 4}},
+		{name: "Quoted author", query: `author:"marcus aurelius" tag:happiness`, expectedIDs: []int{5}},
+		{name: "Field names are case-insensitive", query: "TAG:fear", expectedIDs: []int{1}},
+		{name: "Regex value", query: `text:/^[A-Z][a-z]+ favors/`, expectedIDs: []int{3}},
+		{name: "Regex with escaped slash", query: `text:/\/|-/`, expectedIDs: []int{2}},
+		{name: "Exact", query: "tag:stoic", isExact: true, expectedIDs: nil},
+		{name: "Fuzzy", query: "author:senneca", isFuzzy: true, expectedIDs: []int{1, 4}},
+		{name: "Unknown field is text", query: "Luck: fortune", expectedIDs: []int{4}},
+		{name: "Unknown field before a phrase", query: `note:"fortune favors"`, expectedIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseQuery(tt.query, tt.isExact, tt.isFuzzy)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) returned an unexpected error: %v", tt.query, err)
+			}
+
+			if actualIDs := quoteIDs(Search(sampleQuotes, filter)); !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("ParseQuery(%q) \ngot  = %v, \nwant = %v", tt.query, actualIDs, tt.expectedIDs)
+			}
+			if indexedIDs := quoteIDs(idx.Search(filter)); !reflect.DeepEqual(indexedIDs, tt.expectedIDs) {
+				t.Errorf("ParseQuery(%q) with Index \ngot  = %v, \nwant = %v", tt.query, indexedIDs, tt.expectedIDs)
+			}
+		})
+	}
+}
+
+// TestParseQuery_Errors tests that syntax errors point at the right column.
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedColumn int
+	}{
+		{name: "Empty query", query: "   ", expectedColumn: 4},
+		{name: "Missing value before paren", query: "tag:a AND author:(b)", expectedColumn: 18},
+		{name: "Missing value", query: "author: seneca", expectedColumn: 8},
+		{name: "Unclosed paren", query: "tag:stoic AND (author:seneca", expectedColumn: 29},
+		{name: "Unmatched close paren", query: "tag:stoic)", expectedColumn: 10},
+		{name: "Dangling operator", query: "tag:stoic AND", expectedColumn: 14},
+		{name: "Operator without left side", query: "OR tag:stoic", expectedColumn: 1},
+		{name: "Unterminated phrase", query: `text:"fortune favors`, expectedColumn: 6},
+		{name: "Unterminated regex", query: `text:/abc`, expectedColumn: 6},
+		{name: "Invalid regex", query: `tag:a text:/(x/`, expectedColumn: 7},
+		{name: "Punctuation only", query: `text:!!`, expectedColumn: 1},
+		{name: "Empty parentheses", query: `()`, expectedColumn: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.query, false, false)
+
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("ParseQuery(%q) error = %v, want a *QueryError", tt.query, err)
+			}
+			if queryErr.Column != tt.expectedColumn {
+				t.Errorf("ParseQuery(%q) column = %d (%s), want %d", tt.query, queryErr.Column, queryErr.Msg, tt.expectedColumn)
+			}
+		})
+	}
+}
+
+// TestQueryError_Pointer tests the caret display.
+func TestQueryError_Pointer(t *testing.T) {
+	err := &QueryError{Query: "tag:a title:b", Column: 7, Msg: "unknown field"}
+
+	expected := "tag:a title:b\n      ^"
+	if got := err.Pointer(); got != expected {
+		t.Errorf("Pointer() = %q, want %q", got, expected)
+	}
+}
+
+// TestQueryWords tests that negated terms are not used for ranking.
+func TestQueryWords(t *testing.T) {
+	filter, err := ParseQuery(`tag:stoicism AND NOT author:seneca OR text:"fortune favors"`, false, false)
+	if err != nil {
+		t.Fatalf("ParseQuery returned an unexpected error: %v", err)
+	}
+
+	expected := []string{"stoicism", "fortune favors"}
+	if got := QueryWords(filter); !reflect.DeepEqual(got, expected) {
+		t.Errorf("QueryWords() = %v, want %v", got, expected)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"strings"
 )
 
+// Quote is a single entry in the quotes file. ID is assigned on load or add
+// (see AssignIDs) and persisted, so it can be used to refer to the quote.
 type Quote struct {
+	ID     int      `json:"id"`
 	Text   string   `json:"text"`
 	Author string   `json:"author"`
 	Tags   []string `json:"tags"`
@@ -20,34 +23,12 @@ type Quote struct {
 // If the processed targetTag is empty, or if no matching quotes are found,
 // an empty (non-nil) slice of quotes is returned
 func SearchByQuoteTag(quotes []Quote, targetTag string, isExact bool) []Quote {
-	var matchingQuotes []Quote
-	targetTag = strings.ToLower(strings.TrimSpace(targetTag))
-
 	// return quick if empty tag
-	if targetTag == "" {
-		return matchingQuotes
+	if strings.TrimSpace(targetTag) == "" {
+		return nil
 	}
 
-	// compare tag and targetTag
-	for _, quote := range quotes {
-		for _, quoteTag := range quote.Tags {
-			loweredTag := strings.ToLower(quoteTag)
-
-			if isExact {
-				if loweredTag == targetTag {
-					matchingQuotes = append(matchingQuotes, quote)
-					break
-				}
-			} else {
-				if strings.Contains(loweredTag, targetTag) {
-					matchingQuotes = append(matchingQuotes, quote)
-					break
-				}
-			}
-		}
-	}
-
-	return matchingQuotes
+	return Search(quotes, TagFilter{Tag: targetTag, Exact: isExact})
 }
 
 // SearchByQuoteAuthor filters a slice of quotes, returning only those written by
@@ -57,31 +38,12 @@ func SearchByQuoteTag(quotes []Quote, targetTag string, isExact bool) []Quote {
 // If the processed authorName is empty, or if no matching quotes are found,
 // an empty (non-nil) slice of quotes is returned
 func SearchByQuoteAuthor(quotes []Quote, authorName string, isExact bool) []Quote {
-	var matchingQuotes []Quote
-	authorName = strings.ToLower(strings.TrimSpace(authorName))
-
 	// return quick if empty author
-	if authorName == "" {
-		return matchingQuotes
+	if strings.TrimSpace(authorName) == "" {
+		return nil
 	}
 
-	// compare author and targetAuthor
-	for _, quote := range quotes {
-		loweredAuthor := strings.ToLower(quote.Author)
-
-		// exact or partial author match
-		if isExact {
-			if loweredAuthor == authorName {
-				matchingQuotes = append(matchingQuotes, quote)
-			}
-		} else {
-			if strings.Contains(loweredAuthor, authorName) {
-				matchingQuotes = append(matchingQuotes, quote)
-			}
-		}
-	}
-
-	return matchingQuotes
+	return Search(quotes, AuthorFilter{Author: authorName, Exact: isExact})
 }
 
 // SearchByQuoteAuthor filters a slice of quotes, returning only those written by
@@ -92,7 +54,7 @@ func SearchByQuoteAuthor(quotes []Quote, authorName string, isExact bool) []Quot
 // an empty (non-nil) slice of quotes is returned
 func SearchByPartialQuoteAuthor(quotes []Quote, authorName string) []Quote {
 	var matchingQuotes []Quote
-	authorName = strings.ToLower(strings.TrimSpace(authorName))
+	authorName = normalize(strings.TrimSpace(authorName))
 
 	// return quick if empty author
 	if authorName == "" {
@@ -104,7 +66,7 @@ func SearchByPartialQuoteAuthor(quotes []Quote, authorName string) []Quote {
 
 	// compare author and targetAuthor
 	for _, quote := range quotes {
-		if strings.Contains(strings.ToLower(quote.Author), authorName) {
+		if strings.Contains(normalize(quote.Author), authorName) {
 			matchingQuotes = append(matchingQuotes, quote)
 		}
 	}
@@ -112,6 +74,74 @@ func SearchByPartialQuoteAuthor(quotes []Quote, authorName string) []Quote {
 	return matchingQuotes
 }
 
+// SearchByText filters a slice of quotes, returning only those whose text
+// contains the words of query. Text and query are split into words on spaces
+// and punctuation and compared case-insensitively.
+//
+// When isExact is set every query word has to match a whole word of the quote,
+// otherwise it may match part of one ("inspir" matches "inspiring"). With
+// AllTerms every query word must be found, with AnyTerm at least one, with
+// Phrase all of them next to each other and in order.
+//
+// If query has no words, or if no matching quotes are found, the result is nil.
+func SearchByText(quotes []Quote, query string, isExact bool, mode TermMode) []Quote {
+	// return quick if no words to look for
+	if len(tokenize(query)) == 0 {
+		return nil
+	}
+
+	return Search(quotes, TextFilter{Text: query, Exact: isExact, Mode: mode})
+}
+
+// CountTags returns how many quotes carry each tag. Tags are compared case
+// and accent insensitively (see normalize) and keyed by the lowercased,
+// trimmed form of the first one seen; blank tags are skipped.
+func CountTags(quotes []Quote) map[string]int {
+	counts := make(map[string]int)
+	displayNames := make(map[string]string)
+
+	for _, quote := range quotes {
+		for _, quoteTag := range quote.Tags {
+			tag := strings.ToLower(strings.TrimSpace(quoteTag))
+			if tag == "" {
+				continue
+			}
+
+			key := normalize(tag)
+			if _, ok := displayNames[key]; !ok {
+				displayNames[key] = tag
+			}
+			counts[displayNames[key]]++
+		}
+	}
+
+	return counts
+}
+
+// CountAuthors returns how many quotes each author has. Authors are keyed by
+// their trimmed name as written in the first quote seen, so "Steve Jobs" and
+// "steve jobs" (or "Camus" and "Camús") are counted together; quotes without
+// an author are skipped.
+func CountAuthors(quotes []Quote) map[string]int {
+	counts := make(map[string]int)
+	displayNames := make(map[string]string)
+
+	for _, quote := range quotes {
+		author := strings.TrimSpace(quote.Author)
+		if author == "" {
+			continue
+		}
+
+		key := normalize(author)
+		if _, ok := displayNames[key]; !ok {
+			displayNames[key] = author
+		}
+		counts[displayNames[key]]++
+	}
+
+	return counts
+}
+
 // LoadQuotesFromFile reads a JSON file from the given filepath,
 // parses its content, and returns a slice of Quote structs.
 //
@@ -120,11 +150,29 @@ func SearchByPartialQuoteAuthor(quotes []Quote, authorName string) []Quote {
 //
 // It returns an error if:
 //   - The file cannot be read (e.g., due to non-existence or permissions).
-//   - The file content is not valid JSON or cannot be unmarshaled into []Quote.
-//   - The JSON file is valid but contains an empty array.
+//   - The file content is not valid JSON or cannot be unmarshaled into []Quote,
+//     a *ParseError with the line and column of the problem.
+//   - The JSON file is valid but contains an empty array, wrapping ErrEmptyCollection.
+//
+// Quotes missing an ID (files from before IDs existed) are given one, see AssignIDs.
+// Changes in the file's journal (see UseJournal) are applied to the result.
 func LoadQuotesFromFile(filepath string) ([]Quote, error) {
-	// TODO: change tag slices to maps for quick look ups?
+	quotes, err := loadCollection(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("%w in %q", ErrEmptyCollection, filepath)
+	}
+
+	return quotes, nil
+}
 
+// loadCollection is LoadQuotesFromFile allowing an empty collection, for
+// changes: removing the last quote leaves an empty file that can still be
+// added and imported to.
+func loadCollection(filepath string) ([]Quote, error) {
 	// Read the entire file content
 	data, err := os.ReadFile(filepath)
 	if err != nil {
@@ -135,47 +183,84 @@ func LoadQuotesFromFile(filepath string) ([]Quote, error) {
 	// pares json into struct
 	err = json.Unmarshal(data, &quotes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON from %q: %w", filepath, err)
+		return nil, newParseError(filepath, data, err)
 	}
+	AssignIDs(quotes)
 
-	if len(quotes) == 0 {
-		return nil, fmt.Errorf("no quotes found in %q", filepath)
+	// apply the changes not compacted into the file yet
+	journal, err := readJournal(filepath, data)
+	if err != nil {
+		return nil, err
 	}
+	quotes = replayJournal(quotes, journal.entries)
 
 	return quotes, nil
 }
 
-// Write Json array to file
+// Write Json array to file, assigning IDs to any quotes that lack one. The
+// current file is backed up first (see BackupCount) and then replaced
+// atomically (see writeFileAtomic), so it is never left half written;
+// failures are a *WriteError. The written file replaces any journal, which is
+// archived (see archiveJournal).
 func WriteQuoteToFile(quoteList []Quote, filePath string) error {
+	err := backupQuotesFile(filePath)
+	if err != nil {
+		return &WriteError{Path: filePath, Err: fmt.Errorf("backing up: %w", err)}
+	}
+
+	return writeQuotes(quoteList, filePath)
+}
+
+// writeQuotes is WriteQuoteToFile without the backup.
+func writeQuotes(quoteList []Quote, filePath string) error {
+	assignIDsFrom(recordedNextID(filePath), quoteList)
+
 	// concert to byte slice
 	jsonData, err := json.MarshalIndent(quoteList, "", "\t")
 	if err != nil {
-		return fmt.Errorf("Error marshalling data to JSON: %v\n", err)
+		return &WriteError{Path: filePath, Err: err}
 	}
 
-	// 4. Write the JSON byte slice to the file
-	// os.FileMode(0644) sets the file permissions (read/write for owner, read-only for others).
-	err = os.WriteFile(filePath, jsonData, 0644)
+	// 4. Write the JSON byte slice to the file, atomically so a crash part way
+	// through leaves the old file in place.
+	// os.FileMode(0644) sets the permissions of a new file (read/write for owner,
+	// read-only for others); an existing file keeps its own.
+	err = writeFileAtomic(filePath, jsonData, 0644)
 	if err != nil {
-		return fmt.Errorf("Error writing JSON to file %s: %v\n", filePath, err)
+		return &WriteError{Path: filePath, Err: err}
 	}
+	archiveJournal(filePath)
 
 	return nil
 }
 
+// AddNewQuote appends a quote to the file at filePath, giving it the next free ID.
+// The file stays locked from load to write, so concurrent adds are not lost.
+// With UseJournal the quote is appended to the journal instead, without
+// loading or rewriting the collection.
 func AddNewQuote(newQuoteText string, author string, tags []string, filePath string) error {
+	unlock, err := lockQuotesFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if UseJournal {
+		return journalAddQuote(Quote{Text: newQuoteText, Author: author, Tags: tags}, filePath)
+	}
+
+	quoteList, err := loadCollection(filePath)
+	if err != nil {
+		return err
+	}
+
 	newQ := Quote{
+		ID:     nextFreeID(quoteList, filePath),
 		Text:   newQuoteText,
 		Author: author,
 		Tags:   tags,
 	}
-	quoteList, err := LoadQuotesFromFile(filePath)
-	if err != nil {
-		return err
-	}
 
 	quoteList = append(quoteList, newQ)
-	WriteQuoteToFile(quoteList, filePath)
-
-	return nil
+	return WriteQuoteToFile(quoteList, filePath)
 }
@@ -3,9 +3,12 @@ package quotes
 
 import (
 	"encoding/json"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -398,8 +401,8 @@ func TestLoadQuotesFromFile_Success(t *testing.T) {
 	}
 
 	expectedQuotes := []Quote{
-		{Text: "Test Quote 1", Author: "Test Author 1"},
-		{Text: "Test Quote 2", Author: "Test Author 2"},
+		{ID: 1, Text: "Test Quote 1", Author: "Test Author 1"},
+		{ID: 2, Text: "Test Quote 2", Author: "Test Author 2"},
 	}
 
 	quotes, err := LoadQuotesFromFile(testFilePath)
@@ -630,3 +633,213 @@ func TestSearchByQuoteTag_Substring(t *testing.T) {
 		})
 	}
 }
+
+//				Test - CountTags / CountAuthors
+// ====================================================== \\
+
+// TestCountTags tests that tags are counted case-insensitively and blanks are skipped.
+func TestCountTags(t *testing.T) {
+	sampleQuotes := []Quote{
+		{Text: "Great work", Author: "Steve Jobs", Tags: []string{"work", "Inspiration"}},
+		{Text: "Be yourself", Author: "Oscar Wilde", Tags: []string{"humor", " "}},
+		{Text: "Innovation", Author: "Steve Jobs", Tags: []string{"Work"}},
+	}
+
+	expected := map[string]int{"work": 2, "inspiration": 1, "humor": 1}
+
+	actual := CountTags(sampleQuotes)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("CountTags() \ngot  = %v, \nwant = %v", actual, expected)
+	}
+}
+
+// TestCountAuthors tests that authors are merged case-insensitively and empty authors are skipped.
+func TestCountAuthors(t *testing.T) {
+	sampleQuotes := []Quote{
+		{Text: "Great work", Author: "Steve Jobs"},
+		{Text: "Be yourself", Author: "Oscar Wilde"},
+		{Text: "Innovation", Author: "steve jobs "},
+		{Text: "Anonymous", Author: ""},
+	}
+
+	expected := map[string]int{"Steve Jobs": 2, "Oscar Wilde": 1}
+
+	actual := CountAuthors(sampleQuotes)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("CountAuthors() \ngot  = %v, \nwant = %v", actual, expected)
+	}
+}
+
+//				Test - SearchByText
+// ====================================================== \\
+
+// TestSearchByText tests keyword search over quote text.
+func TestSearchByText(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "The only way to do great work is to love what you do.", Author: "Steve Jobs"},
+		{ID: 2, Text: "Great things are done by a series of small things brought together.", Author: "Vincent van Gogh"},
+		{ID: 3, Text: "Workers of the world, unite!", Author: "Karl Marx"},
+		{ID: 4, Text: "Love all, trust a few, do wrong to none.", Author: "William Shakespeare"},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		isExact     bool
+		mode        TermMode
+		expectedIDs []int
+	}{
+		{name: "Single word substring", query: "work", expectedIDs: []int{1, 3}},
+		{name: "Single word whole word", query: "work", isExact: true, expectedIDs: []int{1}},
+		{name: "Case in-sensitive", query: "GREAT", expectedIDs: []int{1, 2}},
+		{name: "All terms", query: "great love", expectedIDs: []int{1}},
+		{name: "Any term", query: "great love", mode: AnyTerm, expectedIDs: []int{1, 2, 4}},
+		{name: "Punctuation in query is ignored", query: "world, unite!", expectedIDs: []int{3}},
+		{name: "No matching quotes", query: "nonexistent", expectedIDs: nil},
+		{name: "Empty query", query: "  ", expectedIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualIDs := quoteIDs(SearchByText(sampleQuotes, tt.query, tt.isExact, tt.mode))
+
+			if !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("SearchByText(%q) \ngot  = %v, \nwant = %v", tt.query, actualIDs, tt.expectedIDs)
+			}
+		})
+	}
+}
+
+//				Benchmarks - linear scans vs Index
+// ====================================================== \\
+
+// benchmarkQuotes builds a deterministic collection the size of a large shared
+// quotes file.
+func benchmarkQuotes(count int) []Quote {
+	words := []string{
+		"life", "love", "work", "great", "time", "world", "truth", "fear", "hope", "change",
+		"mind", "heart", "power", "freedom", "courage", "wisdom", "nature", "art", "peace", "dream",
+	}
+	authors := []string{"Steve Jobs", "Oscar Wilde", "Seneca", "Marcus Aurelius", "Maya Angelou", "Albert Camus"}
+
+	rng := rand.New(rand.NewSource(1))
+	quoteList := make([]Quote, count)
+	for i := range quoteList {
+		text := make([]string, 12)
+		for j := range text {
+			text[j] = words[rng.Intn(len(words))] + strconv.Itoa(rng.Intn(50))
+		}
+		quoteList[i] = Quote{
+			ID:     i + 1,
+			Text:   strings.Join(text, " "),
+			Author: authors[rng.Intn(len(authors))] + " " + strconv.Itoa(i%500),
+			Tags:   []string{words[rng.Intn(len(words))], words[rng.Intn(len(words))] + strconv.Itoa(i%100)},
+		}
+	}
+	return quoteList
+}
+
+const benchmarkQuoteCount = 50000
+
+// indexedFor returns an Index over quoteList that has already indexed the
+// fields filter searches, so the _Index benchmarks time lookups only.
+func indexedFor(quoteList []Quote, filter Filter) *Index {
+	idx := NewIndex(quoteList)
+	idx.Search(filter) // scans
+	idx.Search(filter) // indexes
+	return idx
+}
+
+func BenchmarkSearchByQuoteTag_Scan(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	b.ResetTimer()
+	for range b.N {
+		SearchByQuoteTag(quoteList, "courage", true)
+	}
+}
+
+func BenchmarkSearchByQuoteTag_Index(b *testing.B) {
+	idx := indexedFor(benchmarkQuotes(benchmarkQuoteCount), TagFilter{Tag: "courage", Exact: true})
+	b.ResetTimer()
+	for range b.N {
+		idx.SearchByQuoteTag("courage", true)
+	}
+}
+
+func BenchmarkSearchByQuoteAuthor_Scan(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	b.ResetTimer()
+	for range b.N {
+		SearchByQuoteAuthor(quoteList, "seneca 42", false)
+	}
+}
+
+func BenchmarkSearchByQuoteAuthor_Index(b *testing.B) {
+	idx := indexedFor(benchmarkQuotes(benchmarkQuoteCount), AuthorFilter{Author: "seneca 42"})
+	b.ResetTimer()
+	for range b.N {
+		idx.SearchByQuoteAuthor("seneca 42", false)
+	}
+}
+
+func BenchmarkSearchByText_Scan(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	b.ResetTimer()
+	for range b.N {
+		SearchByText(quoteList, "love7 truth3", true, AllTerms)
+	}
+}
+
+func BenchmarkSearchByText_Index(b *testing.B) {
+	idx := indexedFor(benchmarkQuotes(benchmarkQuoteCount), TextFilter{Text: "love7 truth3", Exact: true})
+	b.ResetTimer()
+	for range b.N {
+		idx.SearchByText("love7 truth3", true, AllTerms)
+	}
+}
+
+func BenchmarkSearchCombined_Scan(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	filter := AndFilter{TagFilter{Tag: "hope"}, AuthorFilter{Author: "camus"}, TextFilter{Text: "life1"}}
+	b.ResetTimer()
+	for range b.N {
+		Search(quoteList, filter)
+	}
+}
+
+func BenchmarkSearchCombined_Index(b *testing.B) {
+	filter := AndFilter{TagFilter{Tag: "hope"}, AuthorFilter{Author: "camus"}, TextFilter{Text: "life1"}}
+	idx := indexedFor(benchmarkQuotes(benchmarkQuoteCount), filter)
+	b.ResetTimer()
+	for range b.N {
+		idx.Search(filter)
+	}
+}
+
+// The _Once benchmarks build a fresh index for every search, the way the CLI
+// runs one search per process, so they include the cost of building it.
+
+func BenchmarkSearchByQuoteTag_Once(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	b.ResetTimer()
+	for range b.N {
+		NewIndex(quoteList).SearchByQuoteTag("courage", true)
+	}
+}
+
+func BenchmarkSearchByText_Once(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	b.ResetTimer()
+	for range b.N {
+		NewIndex(quoteList).SearchByText("love7 truth3", true, AllTerms)
+	}
+}
+
+func BenchmarkRank_Once(b *testing.B) {
+	quoteList := benchmarkQuotes(benchmarkQuoteCount)
+	filter := AndFilter{TagFilter{Tag: "hope"}, TextFilter{Text: "life1", Exact: true}}
+	b.ResetTimer()
+	for range b.N {
+		NewIndex(quoteList).Rank(filter, "hope life1", true)
+	}
+}
@@ -0,0 +1,299 @@
+package quotes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ====================================================== \\
+//	Relevance Ranking
+// ====================================================== \\
+
+// BM25 parameters: bm25K1 caps how much repeating a word helps, bm25B how
+// much long fields are penalised.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field boosts, so a word that is the author or a tag outranks the same word
+// appearing in passing in the quote text.
+const (
+	textBoost   = 1.0
+	tagBoost    = 2.0
+	authorBoost = 2.5
+)
+
+// fieldIndex holds the word postings and lengths of one quote field.
+type fieldIndex struct {
+	postings  map[string][]posting
+	lengths   []int
+	avgLength float64
+	boost     float64
+
+	// docFreqs, when set, are the document frequencies of the words over
+	// docCount quotes, for an index over part of a collection to weigh words
+	// by how rare they are in all of it.
+	docFreqs map[string]int
+	docCount int
+}
+
+func newFieldIndex(boost float64, docCount int) fieldIndex {
+	return fieldIndex{
+		postings: make(map[string][]posting),
+		lengths:  make([]int, docCount),
+		boost:    boost,
+	}
+}
+
+// add records the tokens of doc. freqs is scratch space reused between calls.
+// Docs must be added in increasing order to keep the postings sorted.
+func (f *fieldIndex) add(doc int, tokens []string, freqs map[string]int) {
+	clear(freqs)
+	for _, token := range tokens {
+		freqs[token]++
+	}
+	for term, freq := range freqs {
+		f.postings[term] = append(f.postings[term], posting{doc: doc, freq: freq})
+	}
+	f.lengths[doc] = len(tokens)
+}
+
+// finish computes the average field length once every doc is added.
+func (f *fieldIndex) finish() {
+	total := 0
+	for _, length := range f.lengths {
+		total += length
+	}
+	if len(f.lengths) > 0 {
+		f.avgLength = float64(total) / float64(len(f.lengths))
+	}
+}
+
+// addScores adds the boosted BM25 score of term in this field to scores,
+// indexed by doc. When not isExact every word containing term counts.
+func (f *fieldIndex) addScores(term string, isExact bool, scores []float64) {
+	if isExact {
+		f.addTermScores(term, f.postings[term], scores)
+		return
+	}
+
+	for key, postings := range f.postings {
+		if strings.Contains(key, term) {
+			f.addTermScores(key, postings, scores)
+		}
+	}
+}
+
+func (f *fieldIndex) addTermScores(key string, postings []posting, scores []float64) {
+	if len(postings) == 0 || f.avgLength == 0 {
+		return
+	}
+
+	docCount := float64(len(f.lengths))
+	docFreq := float64(len(postings))
+	if f.docFreqs != nil {
+		docCount = float64(f.docCount)
+		docFreq = float64(max(f.docFreqs[key], len(postings)))
+	}
+	idf := math.Log(1 + (docCount-docFreq+0.5)/(docFreq+0.5))
+
+	for _, p := range postings {
+		tf := float64(p.freq)
+		norm := 1 - bm25B + bm25B*float64(f.lengths[p.doc])/f.avgLength
+		scores[p.doc] += f.boost * idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+}
+
+// Result is a search hit with its relevance score. It marshals to JSON as the
+// quote's fields plus "score".
+type Result struct {
+	Quote
+	Score float64 `json:"score"`
+}
+
+// Rank returns the quotes matching filter scored against the words of query,
+// best first. Scores use BM25 over the text, author and tags, with author and
+// tag hits weighted above text hits. Words are weighed by how rare they are in
+// the whole collection; how often they appear and the field lengths are only
+// counted for the matching quotes. When isExact query words only score whole
+// words, otherwise any word containing them.
+//
+// Quotes with equal scores (for example every quote when query has no words)
+// keep their file order.
+func (idx *Index) Rank(filter Filter, query string, isExact bool) []Result {
+	docs := idx.docs(filter)
+	if len(docs) == 0 {
+		return nil
+	}
+
+	// score the results as a collection of their own, position i being docs[i]
+	scores := make([]float64, len(docs))
+	if terms := tokenize(query); len(terms) > 0 {
+		text := newFieldIndex(textBoost, len(docs))
+		author := newFieldIndex(authorBoost, len(docs))
+		tags := newFieldIndex(tagBoost, len(docs))
+		freqs := make(map[string]int) // reused for every quote
+		for i, doc := range docs {
+			quote := idx.quotes[doc]
+			text.add(i, tokenize(quote.Text), freqs)
+			author.add(i, authorTokens(quote), freqs)
+			tags.add(i, tagTokens(quote), freqs)
+		}
+
+		text.docFreqs = idx.textFreqs(terms, isExact)
+		author.docFreqs = idx.keyFreqs(&idx.authorField, &idx.authors, authorTokens, terms, isExact)
+		tags.docFreqs = idx.keyFreqs(&idx.tagField, &idx.tags, tagTokens, terms, isExact)
+		for _, field := range []*fieldIndex{&text, &author, &tags} {
+			field.docCount = len(idx.quotes)
+			field.finish()
+			for _, term := range terms {
+				field.addScores(term, isExact, scores)
+			}
+		}
+	}
+
+	results := make([]Result, len(docs))
+	for i, doc := range docs {
+		results[i] = Result{Quote: idx.quotes[doc], Score: scores[i]}
+	}
+	SortResults(results, SortRelevance)
+
+	return results
+}
+
+func authorTokens(quote Quote) []string {
+	return tokenize(quote.Author)
+}
+
+func tagTokens(quote Quote) []string {
+	var tokens []string
+	for _, tag := range quote.Tags {
+		tokens = append(tokens, tokenize(tag)...)
+	}
+	return tokens
+}
+
+// matchesAnyTerm reports whether word is one of terms, or when not isExact
+// contains one.
+func matchesAnyTerm(word string, terms []string, isExact bool) bool {
+	for _, term := range terms {
+		if word == term || !isExact && strings.Contains(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// textFreqs returns how many quotes of the whole collection have each text
+// word matching terms (see matchesAnyTerm), from the text index once it is
+// built and by checking every quote until then.
+func (idx *Index) textFreqs(terms []string, isExact bool) map[string]int {
+	if !idx.textField.isBuilt() {
+		return scanFreqs(idx.quotes, func(quote Quote) []string { return tokenize(quote.Text) }, terms, isExact)
+	}
+
+	freqs := make(map[string]int)
+	for key, postings := range idx.text.postings {
+		if matchesAnyTerm(key, terms, isExact) {
+			freqs[key] = len(postings)
+		}
+	}
+	return freqs
+}
+
+// keyFreqs is textFreqs for the authors or tags: the words of the keys of an
+// author or tag index once field is built, tokensOf a quote until then.
+func (idx *Index) keyFreqs(field *lazyField, keys *map[string][]int, tokensOf func(Quote) []string, terms []string, isExact bool) map[string]int {
+	if !field.isBuilt() {
+		return scanFreqs(idx.quotes, tokensOf, terms, isExact)
+	}
+
+	// a quote can hold a word in several keys ("art", "art history"), so
+	// merge the positions before counting
+	wordDocs := make(map[string][]int)
+	for key, docs := range *keys {
+		for _, word := range tokenize(key) {
+			if matchesAnyTerm(word, terms, isExact) {
+				wordDocs[word] = append(wordDocs[word], docs...)
+			}
+		}
+	}
+	freqs := make(map[string]int, len(wordDocs))
+	for word, docs := range wordDocs {
+		sort.Ints(docs)
+		freqs[word] = len(dedupeDocs(docs))
+	}
+	return freqs
+}
+
+// scanFreqs counts the quotes in quoteList whose tokensOf hold each word
+// matching terms.
+func scanFreqs(quoteList []Quote, tokensOf func(Quote) []string, terms []string, isExact bool) map[string]int {
+	freqs := make(map[string]int)
+	seen := make(map[string]bool) // reused for every quote
+	for _, quote := range quoteList {
+		clear(seen)
+		for _, word := range tokensOf(quote) {
+			if !seen[word] && matchesAnyTerm(word, terms, isExact) {
+				seen[word] = true
+				freqs[word]++
+			}
+		}
+	}
+	return freqs
+}
+
+// SortOrder is the order search results are listed in.
+type SortOrder int
+
+const (
+	// SortRelevance lists the highest score first.
+	SortRelevance SortOrder = iota
+	// SortAuthor lists results by author name, ignoring case and accents.
+	SortAuthor
+	// SortAdded lists results in the order they were added (by ID).
+	SortAdded
+)
+
+// ParseSortOrder converts "relevance", "author" or "added" to a SortOrder.
+func ParseSortOrder(name string) (SortOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "relevance":
+		return SortRelevance, nil
+	case "author":
+		return SortAuthor, nil
+	case "added":
+		return SortAdded, nil
+	}
+	return 0, fmt.Errorf("unknown sort order %q (want relevance, author or added)", name)
+}
+
+// SortResults sorts results in place by order. Ties keep their current order.
+func SortResults(results []Result, order SortOrder) {
+	switch order {
+	case SortRelevance:
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+	case SortAuthor:
+		sort.SliceStable(results, func(i, j int) bool {
+			return normalize(results[i].Author) < normalize(results[j].Author)
+		})
+	case SortAdded:
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].ID < results[j].ID
+		})
+	}
+}
+
+// ResultQuotes returns the quotes of results, in order.
+func ResultQuotes(results []Result) []Quote {
+	quoteList := make([]Quote, len(results))
+	for i, result := range results {
+		quoteList[i] = result.Quote
+	}
+	return quoteList
+}
@@ -0,0 +1,158 @@
+package quotes
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+//				Test - Rank
+// ====================================================== \\
+
+// TestRank tests that results are ordered by relevance.
+func TestRank(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "Life is what happens while you are busy making other plans.", Author: "John Lennon", Tags: []string{"music"}},
+		{ID: 2, Text: "In three words I can sum up everything I've learned about life: it goes on.", Author: "Robert Frost", Tags: []string{"life"}},
+		{ID: 3, Text: "Life, life, life: the more life the better.", Author: "Anonymous", Tags: []string{"humor"}},
+		{ID: 4, Text: "Be yourself; everyone else is already taken.", Author: "Oscar Wilde", Tags: []string{"identity"}},
+		{ID: 5, Text: "A reader lives a thousand lives before he dies.", Author: "George R.R. Martin", Tags: []string{"books"}},
+	}
+	idx := NewIndex(sampleQuotes)
+
+	tests := []struct {
+		name        string
+		filter      Filter
+		query       string
+		isExact     bool
+		expectedIDs []int
+	}{
+		{
+			name:        "Tag hit outranks repeated text hits",
+			filter:      TextFilter{Text: "life", Exact: true},
+			query:       "life",
+			isExact:     true,
+			expectedIDs: []int{2, 3, 1},
+		},
+		{
+			name:        "Author hit ranks first",
+			filter:      OrFilter{TextFilter{Text: "taken"}, AuthorFilter{Author: "wilde"}},
+			query:       "wilde taken",
+			expectedIDs: []int{4},
+		},
+		{
+			name:        "Substring words score when not exact",
+			filter:      TextFilter{Text: "live"},
+			query:       "live",
+			expectedIDs: []int{5},
+		},
+		{
+			name:        "No query words keeps file order",
+			filter:      AndFilter{},
+			query:       "",
+			expectedIDs: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "No matches",
+			filter:      TagFilter{Tag: "nonexistent"},
+			query:       "life",
+			expectedIDs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := idx.Rank(tt.filter, tt.query, tt.isExact)
+			actualIDs := quoteIDs(ResultQuotes(results))
+
+			if !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("Rank(%q) \ngot  = %v, \nwant = %v", tt.query, actualIDs, tt.expectedIDs)
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("Rank(%q) results not sorted by score: %v", tt.query, results)
+				}
+			}
+		})
+	}
+}
+
+// TestRank_CollectionIDF tests that a word rare in the collection outweighs a
+// common one even when every result holds both, whether or not the fields
+// are indexed yet.
+func TestRank_CollectionIDF(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "hope hope dream", Author: "Hope Jones", Tags: []string{"hope"}},
+		{ID: 2, Text: "dream dream hope", Author: "Hope Jones", Tags: []string{"hope"}},
+		{ID: 3, Text: "hope rises", Author: "Hope Jones", Tags: []string{"hope"}},
+		{ID: 4, Text: "hope endures", Author: "Ann Lee", Tags: []string{"hope"}},
+		{ID: 5, Text: "hope again", Author: "Ann Lee", Tags: []string{"life"}},
+	}
+	filter := TextFilter{Text: "hope dream", Exact: true}
+	expectedIDs := []int{2, 1}
+
+	for _, isIndexed := range []bool{false, true} {
+		idx := NewIndex(sampleQuotes)
+		if isIndexed {
+			idx = indexedFor(sampleQuotes, OrFilter{filter, TagFilter{Tag: "hope"}, AuthorFilter{Author: "hope"}})
+		}
+
+		actualIDs := quoteIDs(ResultQuotes(idx.Rank(filter, "hope dream", true)))
+		if !reflect.DeepEqual(actualIDs, expectedIDs) {
+			t.Errorf("Rank() indexed %v \ngot  = %v, \nwant = %v", isIndexed, actualIDs, expectedIDs)
+		}
+	}
+}
+
+// TestSortResults tests the author and added orders.
+func TestSortResults(t *testing.T) {
+	results := []Result{
+		{Quote: Quote{ID: 3, Author: "oscar wilde"}, Score: 1},
+		{Quote: Quote{ID: 1, Author: "Steve Jobs"}, Score: 3},
+		{Quote: Quote{ID: 2, Author: "Albert Camus"}, Score: 2},
+	}
+
+	SortResults(results, SortAuthor)
+	if ids := quoteIDs(ResultQuotes(results)); !reflect.DeepEqual(ids, []int{2, 3, 1}) {
+		t.Errorf("SortResults(SortAuthor) = %v, want [2 3 1]", ids)
+	}
+
+	SortResults(results, SortAdded)
+	if ids := quoteIDs(ResultQuotes(results)); !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Errorf("SortResults(SortAdded) = %v, want [1 2 3]", ids)
+	}
+
+	SortResults(results, SortRelevance)
+	if ids := quoteIDs(ResultQuotes(results)); !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Errorf("SortResults(SortRelevance) = %v, want [1 2 3]", ids)
+	}
+}
+
+// TestParseSortOrder tests parsing the --sort values.
+func TestParseSortOrder(t *testing.T) {
+	for name, expected := range map[string]SortOrder{"relevance": SortRelevance, "Author": SortAuthor, " added ": SortAdded} {
+		order, err := ParseSortOrder(name)
+		if err != nil || order != expected {
+			t.Errorf("ParseSortOrder(%q) = %v, %v; want %v", name, order, err, expected)
+		}
+	}
+
+	if _, err := ParseSortOrder("newest"); err == nil {
+		t.Error("ParseSortOrder expected an error for an unknown order, but got none.")
+	}
+}
+
+// TestResult_JSON tests that a result marshals as a flat quote with a score.
+func TestResult_JSON(t *testing.T) {
+	result := Result{Quote: Quote{ID: 7, Text: "Hi", Author: "Me", Tags: []string{"x"}}, Score: 1.5}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal returned an unexpected error: %v", err)
+	}
+
+	expected := `{"id":7,"text":"Hi","author":"Me","tags":["x"],"score":1.5}`
+	if string(data) != expected {
+		t.Errorf("json.Marshal(Result) = %s, want %s", data, expected)
+	}
+}
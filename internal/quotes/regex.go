@@ -0,0 +1,99 @@
+package quotes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ====================================================== \\
+//	Regular Expression Search
+// ====================================================== \\
+
+// Field names a part of a quote a search can look at.
+type Field int
+
+const (
+	FieldText Field = iota
+	FieldAuthor
+	FieldTag
+	// FieldAny is any of text, author or tags.
+	FieldAny
+)
+
+var fieldNames = map[Field]string{
+	FieldText:   "text",
+	FieldAuthor: "author",
+	FieldTag:    "tag",
+	FieldAny:    "any",
+}
+
+func (f Field) String() string {
+	if name, ok := fieldNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Field(%d)", int(f))
+}
+
+// ParseField converts "text", "author", "tag" (or "tags") and "any" to a Field.
+func ParseField(name string) (Field, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "tags" {
+		name = "tag"
+	}
+	for field, fieldName := range fieldNames {
+		if fieldName == name {
+			return field, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown field %q (want text, author, tag or any)", name)
+}
+
+// RegexFilter matches quotes where Pattern matches somewhere in Field (for
+// FieldTag, in any one tag). Unlike the other filters it is case-sensitive and
+// sees the raw field, whitespace and punctuation included, so it can find
+// formatting problems: use (?i) in the pattern for case-insensitive matching.
+type RegexFilter struct {
+	Field   Field
+	Pattern *regexp.Regexp
+}
+
+// NewRegexFilter compiles pattern (Go RE2 syntax) into a RegexFilter on field.
+func NewRegexFilter(field Field, pattern string) (RegexFilter, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return RegexFilter{}, fmt.Errorf("invalid regular expression %q for %s: %w", pattern, field, err)
+	}
+	return RegexFilter{Field: field, Pattern: re}, nil
+}
+
+func (f RegexFilter) Match(quote Quote) bool {
+	switch f.Field {
+	case FieldText:
+		return f.Pattern.MatchString(quote.Text)
+	case FieldAuthor:
+		return f.Pattern.MatchString(quote.Author)
+	case FieldTag:
+		for _, quoteTag := range quote.Tags {
+			if f.Pattern.MatchString(quoteTag) {
+				return true
+			}
+		}
+		return false
+	case FieldAny:
+		return RegexFilter{Field: FieldText, Pattern: f.Pattern}.Match(quote) ||
+			RegexFilter{Field: FieldAuthor, Pattern: f.Pattern}.Match(quote) ||
+			RegexFilter{Field: FieldTag, Pattern: f.Pattern}.Match(quote)
+	}
+	return false
+}
+
+// SearchByRegex filters a slice of quotes, returning those where pattern
+// matches the given field. It returns an error if pattern does not compile.
+func SearchByRegex(quotes []Quote, field Field, pattern string) ([]Quote, error) {
+	filter, err := NewRegexFilter(field, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return Search(quotes, filter), nil
+}
@@ -0,0 +1,76 @@
+package quotes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSearchByRegex tests regular expression search on each field.
+func TestSearchByRegex(t *testing.T) {
+	sampleQuotes := []Quote{
+		{ID: 1, Text: "The only way to do great work  is to love what you do.", Author: "Steve Jobs", Tags: []string{"work"}},
+		{ID: 2, Text: "\"Be yourself; everyone else is already taken.", Author: "Oscar Wilde", Tags: []string{"identity", "humor "}},
+		{ID: 3, Text: "To be or not to be, that is the question", Author: "William Shakespeare", Tags: []string{"drama"}},
+		{ID: 4, Text: "Stay hungry, stay foolish.", Author: "steve jobs", Tags: []string{"Tech"}},
+	}
+
+	tests := []struct {
+		name        string
+		field       Field
+		pattern     string
+		expectedIDs []int
+	}{
+		{name: "Double spaces in text", field: FieldText, pattern: `\S  \S`, expectedIDs: []int{1}},
+		{name: "Stray leading quote", field: FieldText, pattern: `^"`, expectedIDs: []int{2}},
+		{name: "Missing trailing punctuation", field: FieldText, pattern: `[^.!?]$`, expectedIDs: []int{3}},
+		{name: "Case-sensitive by default", field: FieldAuthor, pattern: `^Steve`, expectedIDs: []int{1}},
+		{name: "Case-insensitive flag", field: FieldAuthor, pattern: `(?i)^steve`, expectedIDs: []int{1, 4}},
+		{name: "Tag with trailing space", field: FieldTag, pattern: `\s$`, expectedIDs: []int{2}},
+		{name: "Tag starting upper case", field: FieldTag, pattern: `^[A-Z]`, expectedIDs: []int{4}},
+		{name: "Any field", field: FieldAny, pattern: `Tech|Wilde`, expectedIDs: []int{2, 4}},
+		{name: "No match", field: FieldText, pattern: `xyz`, expectedIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := SearchByRegex(sampleQuotes, tt.field, tt.pattern)
+			if err != nil {
+				t.Fatalf("SearchByRegex returned an unexpected error: %v", err)
+			}
+
+			if actualIDs := quoteIDs(actual); !reflect.DeepEqual(actualIDs, tt.expectedIDs) {
+				t.Errorf("SearchByRegex(%q) \ngot  = %v, \nwant = %v", tt.pattern, actualIDs, tt.expectedIDs)
+			}
+		})
+	}
+}
+
+// TestSearchByRegex_InvalidPattern tests that bad patterns are reported.
+func TestSearchByRegex_InvalidPattern(t *testing.T) {
+	quotes, err := SearchByRegex([]Quote{{Text: "a"}}, FieldText, `(unclosed`)
+
+	if err == nil {
+		t.Fatal("SearchByRegex expected an error for an invalid pattern, but got none.")
+	}
+	if quotes != nil {
+		t.Errorf("SearchByRegex expected nil quotes for an invalid pattern, but got: %+v", quotes)
+	}
+	if !strings.Contains(err.Error(), `"(unclosed"`) || !strings.Contains(err.Error(), "missing closing )") {
+		t.Errorf("Unexpected error message for invalid pattern: %q", err.Error())
+	}
+}
+
+// TestParseField tests parsing field names.
+func TestParseField(t *testing.T) {
+	for name, expected := range map[string]Field{"text": FieldText, "Author": FieldAuthor, "tags": FieldTag, "any": FieldAny} {
+		field, err := ParseField(name)
+		if err != nil || field != expected {
+			t.Errorf("ParseField(%q) = %v, %v; want %v", name, field, err, expected)
+		}
+	}
+
+	if _, err := ParseField("title"); err == nil {
+		t.Error("ParseField expected an error for an unknown field, but got none.")
+	}
+}
@@ -0,0 +1,87 @@
+package quotes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ====================================================== \\
+//	Starter Collection
+// ====================================================== \\
+
+// starterData is the collection a new quotes file is seeded with.
+//
+//go:embed starter_quotes.json
+var starterData []byte
+
+// StarterQuotes returns a fresh copy of the starter collection bundled with
+// the binary.
+func StarterQuotes() []Quote {
+	var quoteList []Quote
+	if err := json.Unmarshal(starterData, &quoteList); err != nil {
+		// starter_quotes.json is checked by the tests
+		panic(fmt.Sprintf("invalid embedded starter quotes: %v", err))
+	}
+	return quoteList
+}
+
+// WriteStarterFile writes the starter collection to filePath, creating any
+// missing parent directories. An existing file is only replaced when isForce
+// is set; otherwise the error wraps fs.ErrExist.
+func WriteStarterFile(isForce bool, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %q: %w", filePath, err)
+	}
+
+	unlock, err := lockQuotesFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !isForce {
+		if _, err := os.Stat(filePath); err == nil {
+			return fmt.Errorf("quotes file %q: %w", filePath, fs.ErrExist)
+		}
+	}
+
+	if err := recordIDsInUse(filePath); err != nil {
+		return &WriteError{Path: filePath, Err: err}
+	}
+
+	// numbered after every ID the file has used, so a replaced collection's
+	// IDs do not come to mean other quotes
+	starter := StarterQuotes()
+	for i := range starter {
+		starter[i].ID = 0
+	}
+	return WriteQuoteToFile(starter, filePath)
+}
+
+// EnsureQuotesFile seeds filePath with the starter collection if it does not
+// exist yet, so a fresh install has something to show. It reports whether the
+// file was created.
+func EnsureQuotesFile(filePath string) (bool, error) {
+	_, err := os.Stat(filePath)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to check quotes file %q: %w", filePath, err)
+	}
+
+	err = WriteStarterFile(false, filePath)
+	if errors.Is(err, fs.ErrExist) {
+		// another process got there first
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,112 @@
+package quotes
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestStarterQuotes tests that the embedded starter collection is usable.
+func TestStarterQuotes(t *testing.T) {
+	quoteList := StarterQuotes()
+	if len(quoteList) == 0 {
+		t.Fatal("StarterQuotes() returned no quotes")
+	}
+
+	seen := make(map[int]bool)
+	for _, quote := range quoteList {
+		if quote.ID <= 0 || seen[quote.ID] {
+			t.Errorf("quote %q has missing or repeated id %d", quote.Text, quote.ID)
+		}
+		seen[quote.ID] = true
+
+		if strings.TrimSpace(quote.Text) == "" || strings.TrimSpace(quote.Author) == "" || len(quote.Tags) == 0 {
+			t.Errorf("quote %d is missing its text, author or tags: %+v", quote.ID, quote)
+		}
+	}
+
+	// every call returns its own copy
+	quoteList[0].Text = "changed"
+	if StarterQuotes()[0].Text == "changed" {
+		t.Error("StarterQuotes() shares memory between calls")
+	}
+}
+
+// TestWriteStarterFile tests creating, refusing to overwrite and replacing a
+// quotes file with the starter collection.
+func TestWriteStarterFile(t *testing.T) {
+	testFilePath := filepath.Join(t.TempDir(), "config", "quote-cli", "default.json")
+
+	if err := WriteStarterFile(false, testFilePath); err != nil {
+		t.Fatalf("WriteStarterFile() returned an unexpected error: %v", err)
+	}
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(quoteList, StarterQuotes()) {
+		t.Errorf("written file does not hold the starter quotes")
+	}
+
+	// change the file so overwriting can be told apart
+	if err := WriteQuoteToFile(quoteList[:1], testFilePath); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	err = WriteStarterFile(false, testFilePath)
+	if !errors.Is(err, fs.ErrExist) {
+		t.Errorf("WriteStarterFile() on an existing file error = %v; want fs.ErrExist", err)
+	}
+	if quoteList, _ := LoadQuotesFromFile(testFilePath); len(quoteList) != 1 {
+		t.Errorf("existing file was changed without isForce, got %d quotes", len(quoteList))
+	}
+
+	if err := WriteStarterFile(true, testFilePath); err != nil {
+		t.Fatalf("WriteStarterFile(true) returned an unexpected error: %v", err)
+	}
+	if quoteList, _ := LoadQuotesFromFile(testFilePath); len(quoteList) != len(StarterQuotes()) {
+		t.Errorf("isForce did not replace the file, got %d quotes", len(quoteList))
+	}
+}
+
+// TestWriteStarterFile_RemovedIDs tests that replacing a collection with the
+// starter quotes does not give them the IDs of removed quotes.
+func TestWriteStarterFile_RemovedIDs(t *testing.T) {
+	testFilePath := filepath.Join(t.TempDir(), "default.json")
+	if err := WriteStarterFile(false, testFilePath); err != nil {
+		t.Fatalf("WriteStarterFile() returned an unexpected error: %v", err)
+	}
+	if _, err := RemoveQuotes([]int{1, 2}, testFilePath); err != nil {
+		t.Fatalf("RemoveQuotes() returned an unexpected error: %v", err)
+	}
+
+	if err := WriteStarterFile(true, testFilePath); err != nil {
+		t.Fatalf("WriteStarterFile(true) returned an unexpected error: %v", err)
+	}
+	quoteList, err := LoadQuotesFromFile(testFilePath)
+	if err != nil {
+		t.Fatalf("LoadQuotesFromFile returned an unexpected error: %v", err)
+	}
+	starterCount := len(StarterQuotes())
+	if first := quoteList[0].ID; first != starterCount+1 {
+		t.Errorf("replaced starter quotes start at id %d; want %d", first, starterCount+1)
+	}
+}
+
+// TestEnsureQuotesFile tests seeding a missing quotes file only once.
+func TestEnsureQuotesFile(t *testing.T) {
+	testFilePath := filepath.Join(t.TempDir(), "quote-cli", "default.json")
+
+	created, err := EnsureQuotesFile(testFilePath)
+	if err != nil || !created {
+		t.Fatalf("EnsureQuotesFile() = %v, %v; want true, nil", created, err)
+	}
+
+	created, err = EnsureQuotesFile(testFilePath)
+	if err != nil || created {
+		t.Errorf("EnsureQuotesFile() on an existing file = %v, %v; want false, nil", created, err)
+	}
+}
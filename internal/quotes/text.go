@@ -0,0 +1,92 @@
+package quotes
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ====================================================== \\
+//	Text Tokenizing
+// ====================================================== \\
+
+// TermMode says how a multi-word text query has to match.
+type TermMode int
+
+const (
+	// AllTerms keeps quotes that match every word of the query.
+	AllTerms TermMode = iota
+	// AnyTerm keeps quotes that match at least one word of the query.
+	AnyTerm
+	// Phrase keeps quotes that match the words of the query next to each
+	// other and in order.
+	Phrase
+)
+
+// tokenize splits text into normalized words (see normalize). Anything that
+// is not a letter or digit separates words, except apostrophes inside a word
+// ("don't" is one word). Curly apostrophes are treated as straight ones.
+func tokenize(text string) []string {
+	text = normalize(strings.ReplaceAll(text, "’", "'"))
+
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.Trim(field, "'"); field != "" {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// matchTerms reports whether textTokens match the query terms under mode.
+// When isExact a term has to equal a whole word, otherwise it may be any part
+// of a word ("work" matches "workers").
+func matchTerms(textTokens []string, terms []string, isExact bool, mode TermMode) bool {
+	if len(terms) == 0 {
+		return false
+	}
+	if mode == Phrase {
+		return matchPhrase(textTokens, terms, isExact)
+	}
+
+	for _, term := range terms {
+		found := false
+		for _, token := range textTokens {
+			if token == term || (!isExact && strings.Contains(token, term)) {
+				found = true
+				break
+			}
+		}
+
+		if found && mode == AnyTerm {
+			return true
+		}
+		if !found && mode == AllTerms {
+			return false
+		}
+	}
+
+	return mode == AllTerms
+}
+
+// matchPhrase reports whether terms match a run of neighbouring textTokens, in
+// order, each term matching as in matchTerms.
+func matchPhrase(textTokens []string, terms []string, isExact bool) bool {
+	for start := 0; start+len(terms) <= len(textTokens); start++ {
+		matched := true
+		for i, term := range terms {
+			token := textTokens[start+i]
+			if token != term && (isExact || !strings.Contains(token, term)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
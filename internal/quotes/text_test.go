@@ -0,0 +1,30 @@
+package quotes
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTokenize tests splitting text into normalized words.
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "Empty string", text: "", expected: []string{}},
+		{name: "Punctuation splits words", text: "Be yourself; everyone else is taken.", expected: []string{"be", "yourself", "everyone", "else", "is", "taken"}},
+		{name: "Apostrophes inside words are kept", text: "Don't stop, it’s 'fine'", expected: []string{"don't", "stop", "it's", "fine"}},
+		{name: "Digits and letters", text: "Catch-22 in 1961", expected: []string{"catch", "22", "in", "1961"}},
+		{name: "Non ASCII letters are folded", text: "Déjà vu, Straße", expected: []string{"deja", "vu", "strasse"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.text)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("tokenize(%q) = %v; want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}